@@ -0,0 +1,209 @@
+package jenkins
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// BuildArtifact describes file archived by a build
+type BuildArtifact struct {
+	DisplayPath  string `json:"displayPath"`
+	FileName     string `json:"fileName"`
+	RelativePath string `json:"relativePath"`
+}
+
+// BuildFingerprint holds MD5 checksum of a file recorded by Jenkins
+type BuildFingerprint struct {
+	FileName string `json:"fileName"`
+	Hash     string `json:"hash"`
+}
+
+// ArtifactsDownloadOptions configure ArtifactsDownloadAll
+type ArtifactsDownloadOptions struct {
+	// Zip enables downloading all artifacts in a single archive
+	// instead of requesting them one by one
+	Zip bool
+	// VerifyChecksum enables comparison of downloaded files with MD5
+	// fingerprints recorded by Jenkins (files without fingerprints are not checked)
+	VerifyChecksum bool
+}
+
+// artifactRoute escapes every segment of artifact relative path
+func artifactRoute(name JobPath, number int, relativePath string) string {
+	segments := strings.Split(relativePath, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return fmt.Sprintf("%s/%d/artifact/%s", name.Route(), number, strings.Join(segments, "/"))
+}
+
+// artifactLocalPath resolves artifact path within a target directory,
+// refusing paths that point outside of it
+func artifactLocalPath(dir, relativePath string) (string, error) {
+	localPath := filepath.Join(dir, filepath.FromSlash(relativePath))
+	if localPath != filepath.Clean(dir) && !strings.HasPrefix(localPath, filepath.Clean(dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("Artifact path %s points outside of %s", relativePath, dir)
+	}
+	return localPath, nil
+}
+
+// artifactWriter writes artifact to a local file computing its size and checksum on the fly
+type artifactWriter struct {
+	path string
+	size int64
+	hash hash.Hash
+}
+
+func newArtifactWriter(path string) *artifactWriter {
+	return &artifactWriter{path: path, hash: md5.New()}
+}
+
+// writeFrom streams content into a local file
+func (w *artifactWriter) writeFrom(content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(w.path)
+	if err != nil {
+		return err
+	}
+	w.size, err = io.Copy(io.MultiWriter(file, w.hash), content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// verify compares written file with expected size (if known) and checksum (if given)
+func (w *artifactWriter) verify(expectedSize int64, expectedHash string) error {
+	return verifyArtifact(w.path, w.size, w.hash, expectedSize, expectedHash)
+}
+
+// artifactReader computes size and checksum of artifact content on the fly
+// and verifies them when the content has been read to the end
+type artifactReader struct {
+	io.ReadCloser
+	name         string
+	expectedSize int64
+	expectedHash string
+	size         int64
+	hash         hash.Hash
+}
+
+func newArtifactReader(content io.ReadCloser, name string, expectedSize int64, expectedHash string) *artifactReader {
+	return &artifactReader{
+		ReadCloser:   content,
+		name:         name,
+		expectedSize: expectedSize,
+		expectedHash: expectedHash,
+		hash:         md5.New(),
+	}
+}
+
+// Read returns error wrapping ErrArtifactCorrupted instead of io.EOF if verification fails
+func (r *artifactReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += int64(n)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if verifyErr := verifyArtifact(r.name, r.size, r.hash, r.expectedSize, r.expectedHash); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+	return n, err
+}
+
+// verifyArtifact compares artifact content with expected size (if known) and checksum (if given)
+func verifyArtifact(name string, size int64, sum hash.Hash, expectedSize int64, expectedHash string) error {
+	if expectedSize >= 0 && size != expectedSize {
+		return fmt.Errorf("%s: expected %d bytes, got %d: %w", name, expectedSize, size, ErrArtifactCorrupted)
+	}
+	if actualHash := hex.EncodeToString(sum.Sum(nil)); expectedHash != "" && !strings.EqualFold(actualHash, expectedHash) {
+		return fmt.Errorf("%s: expected MD5 %s, got %s: %w", name, expectedHash, actualHash, ErrArtifactCorrupted)
+	}
+	return nil
+}
+
+// fingerprintsByPath maps artifact paths to their MD5 checksums;
+// paths recorded with different checksums are mapped to empty string (not verified)
+func fingerprintsByPath(fingerprints []BuildFingerprint) map[string]string {
+	result := make(map[string]string, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		if hash, ok := result[fingerprint.FileName]; ok && hash != fingerprint.Hash {
+			result[fingerprint.FileName] = ""
+			continue
+		}
+		result[fingerprint.FileName] = fingerprint.Hash
+	}
+	return result
+}
+
+// artifactHash looks for a checksum of artifact by its relative path or, if there is exactly
+// one fingerprint with the same file name, by its file name; empty result disables verification
+func artifactHash(hashes map[string]string, relativePath string) string {
+	if hash, ok := hashes[relativePath]; ok {
+		return hash
+	}
+	var (
+		found   string
+		matches int
+	)
+	for fileName, hash := range hashes {
+		if path.Base(fileName) == path.Base(relativePath) {
+			found = hash
+			matches++
+		}
+	}
+	if matches != 1 {
+		return ""
+	}
+	return found
+}
+
+// extractArtifactsArchive unpacks archive returned by *zip*/archive.zip endpoint
+// into a given directory; all the artifacts are placed in "archive/" folder inside it
+func extractArtifactsArchive(archivePath, dir string, hashes map[string]string) ([]string, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var paths []string
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		relativePath := strings.TrimPrefix(file.Name, "archive/")
+		localPath, err := artifactLocalPath(dir, relativePath)
+		if err != nil {
+			return paths, err
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return paths, err
+		}
+		writer := newArtifactWriter(localPath)
+		err = writer.writeFrom(content)
+		content.Close()
+		if err != nil {
+			return paths, err
+		}
+		// Size is already checked by archive/zip against the archive header
+		if err = writer.verify(-1, artifactHash(hashes, relativePath)); err != nil {
+			return paths, err
+		}
+		paths = append(paths, localPath)
+	}
+	return paths, nil
+}
@@ -5,18 +5,15 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"time"
 )
 
 // Build ???
 type Build struct {
-	Actions   []BuildAction
-	Artifacts []struct {
-		DisplayPath  string `json:"displayPath"`
-		FileName     string `json:"fileName"`
-		RelativePath string `json:"relativePath"`
-	} `json:"artifacts"`
-	Building  bool   `json:"building"`
-	BuiltOn   string `json:"builtOn"`
+	Actions   []BuildAction   `json:"actions"`
+	Artifacts []BuildArtifact `json:"artifacts"`
+	Building  bool            `json:"building"`
+	BuiltOn   string          `json:"builtOn"`
 	ChangeSet struct {
 		Items []struct {
 			AffectedPaths []string `json:"affectedPaths"`
@@ -37,8 +34,8 @@ type Build struct {
 		} `json:"items"`
 		Kind      string `json:"kind"`
 		Revisions []struct {
-			Module   string
-			Revision int
+			Module   string `json:"module"`
+			Revision int    `json:"revision"`
 		} `json:"revision"`
 	} `json:"changeSet"`
 	Culprits          []BuildCuilprit `json:"culprits"`
@@ -50,27 +47,36 @@ type Build struct {
 	ID                string          `json:"id"`
 	KeepLog           bool            `json:"keepLog"`
 	Number            int             `json:"number"`
+	QueueID           int             `json:"queueId"`
 	Result            string          `json:"result"`
 	Timestamp         int             `json:"timestamp"`
 	URL               string          `json:"url"`
 	MavenArtifacts    interface{}     `json:"mavenArtifacts"`
 	MavenVersionUsed  string          `json:"mavenVersionUsed"`
 	Runs              []struct {
-		Number int
-		URL    string
+		Number int    `json:"number"`
+		URL    string `json:"url"`
 	} `json:"runs"`
 }
 
-// BuildParameter ???
-type BuildParameter struct {
-	Name  string
-	Value bool
+// buildExecutorTree selects fields of Build.Executor
+var buildExecutorTree = NewTree("idle", "likelyStuck", "number", "progress").
+	Nested("currentExecutable", NewTree("number", "url"))
+
+// JenkinsTree selects fields of a build including the ones decoded into interfaces
+func (b *Build) JenkinsTree() *Tree {
+	return fieldsTree(b).Nested("executor", buildExecutorTree)
+}
+
+// StartTime returns the moment when the build has been started
+func (b *Build) StartTime() time.Time {
+	return time.Unix(0, int64(b.Timestamp)*int64(time.Millisecond))
 }
 
 // BuildBranch ???
 type BuildBranch struct {
-	SHA1 string
-	Name string
+	SHA1 string `json:"SHA1"`
+	Name string `json:"name"`
 }
 
 // BuildRevision ???
@@ -89,8 +95,8 @@ type Builds struct {
 
 // BuildCuilprit ???
 type BuildCuilprit struct {
-	AbsoluteURL string
-	FullName    string
+	AbsoluteURL string `json:"absoluteUrl"`
+	FullName    string `json:"fullName"`
 }
 
 // BuildAction ???
@@ -104,8 +110,18 @@ type BuildAction struct {
 	MercurialNodeName       string                   `json:"mercurialNodeName"`
 	MercurialRevisionNumber string                   `json:"mercurialRevisionNumber"`
 	Subdir                  interface{}              `json:"subdir"`
-	TotalCount              int
-	URLName                 string
+	TotalCount              int                      `json:"totalCount"`
+	URLName                 string                   `json:"urlName"`
+}
+
+// buildCauseTree selects fields of BuildAction.Causes exported by the most common causes
+// (started by user, by upstream build, by timer)
+var buildCauseTree = NewTree("_class", "shortDescription", "userId", "userName",
+	"upstreamBuild", "upstreamProject", "upstreamUrl")
+
+// JenkinsTree selects fields of an action including the ones decoded into maps
+func (a *BuildAction) JenkinsTree() *Tree {
+	return fieldsTree(a).Nested("causes", buildCauseTree)
 }
 
 // BuildInvoked is returned as a part of a response headers when the build is invoked
@@ -128,3 +144,96 @@ func NewBuildInvokedFromURL(URL *url.URL) (*BuildInvoked, error) {
 	}
 	return &BuildInvoked{URL: URL, ID: buildID}, nil
 }
+
+// WaitOptions configure polling performed by WaitForBuild
+type WaitOptions struct {
+	// Interval is a delay between two subsequent requests (1 second by default)
+	Interval time.Duration
+	// Backoff multiplies interval after every request (1 by default, i. e. constant interval)
+	Backoff float64
+	// MaxInterval limits interval growth (not limited by default)
+	MaxInterval time.Duration
+	// OnQueued is called every time when the build is found to be still waiting in the queue
+	OnQueued func(*QueueItem)
+	// OnBuilding is called every time when the build is found to be still running
+	OnBuilding func(*Build)
+}
+
+func (o *WaitOptions) withDefaults() *WaitOptions {
+	var result WaitOptions
+	if o != nil {
+		result = *o
+	}
+	if result.Interval <= 0 {
+		result.Interval = time.Second
+	}
+	if result.Backoff < 1 {
+		result.Backoff = 1
+	}
+	return &result
+}
+
+func (o *WaitOptions) nextInterval(current time.Duration) time.Duration {
+	next := time.Duration(float64(current) * o.Backoff)
+	if o.MaxInterval > 0 && next > o.MaxInterval {
+		next = o.MaxInterval
+	}
+	return next
+}
+
+// BuildAbortStep enumerates steps of build abortion escalation
+type BuildAbortStep uint
+
+const (
+	// BuildAbortNone means that build was not running at all
+	BuildAbortNone BuildAbortStep = iota
+	// BuildAbortStop means that build was aborted gracefully via /stop
+	BuildAbortStop
+	// BuildAbortTerm means that build was forcibly terminated via /term (pipelines only)
+	BuildAbortTerm
+	// BuildAbortKill means that build was hard killed via /kill (pipelines only)
+	BuildAbortKill
+)
+
+func (s BuildAbortStep) String() string {
+	switch s {
+	case BuildAbortNone:
+		return "none"
+	case BuildAbortStop:
+		return "stop"
+	case BuildAbortTerm:
+		return "term"
+	case BuildAbortKill:
+		return "kill"
+	default:
+		return "unknown"
+	}
+}
+
+// AbortOptions configure escalation performed by BuildAbort
+type AbortOptions struct {
+	// StopTimeout is a time given to build to finish after /stop (30 seconds by default)
+	StopTimeout time.Duration
+	// TermTimeout is a time given to build to finish after /term (30 seconds by default)
+	TermTimeout time.Duration
+	// KillTimeout is a time given to build to finish after /kill (30 seconds by default)
+	KillTimeout time.Duration
+	// Interval is a delay between two subsequent build status requests (1 second by default)
+	Interval time.Duration
+}
+
+func (o *AbortOptions) withDefaults() *AbortOptions {
+	var result AbortOptions
+	if o != nil {
+		result = *o
+	}
+	for _, timeout := range []*time.Duration{&result.StopTimeout, &result.TermTimeout, &result.KillTimeout} {
+		if *timeout <= 0 {
+			*timeout = 30 * time.Second
+		}
+	}
+	if result.Interval <= 0 {
+		result.Interval = time.Second
+	}
+	return &result
+}
@@ -2,10 +2,17 @@ package jenkins
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vitalyisaev2/jenkins-client-golang/request"
 )
@@ -15,27 +22,145 @@ type Client interface {
 	// RootInfo returns basic information about the node that you've connected to
 	RootInfo(ctx context.Context) (*Root, error)
 	// JobCreate creates new job with  given name and xml configuration dumped into string
-	JobCreate(ctx context.Context, name, config string) (*Job, error)
+	JobCreate(ctx context.Context, name JobPath, config string) (*Job, error)
 	// JobGet requests common job information for a given job name
-	JobGet(ctx context.Context, name string, depth int) (*Job, error)
+	JobGet(ctx context.Context, name JobPath, depth int) (*Job, error)
+	// JobGetInto requests job fields selected by receiver's type (see TreeOf) and decodes them into receiver
+	JobGetInto(ctx context.Context, name JobPath, receiver interface{}) error
 	// JobDelete deletes the requested job
-	JobDelete(ctx context.Context, name string) error
+	JobDelete(ctx context.Context, name JobPath) error
+	// JobCopy creates new job with configuration of existing one
+	JobCopy(ctx context.Context, from, to JobPath) (*Job, error)
+	// JobRename changes short name of a job (job stays in the same folder)
+	JobRename(ctx context.Context, name JobPath, newName string) (*Job, error)
+	// JobEnable allows builds of a job
+	JobEnable(ctx context.Context, name JobPath) (*Job, error)
+	// JobDisable prohibits builds of a job
+	JobDisable(ctx context.Context, name JobPath) (*Job, error)
+	// JobSetDescription replaces description of a job
+	JobSetDescription(ctx context.Context, name JobPath, description string) (*Job, error)
+	// FolderCreate creates new empty folder (requires Folders plugin)
+	FolderCreate(ctx context.Context, name JobPath) (*Job, error)
+	// FolderList returns items of a folder; empty path corresponds to Jenkins root
+	FolderList(ctx context.Context, name JobPath) ([]JobBrief, error)
+	// JobListRecursive returns items of a folder and all its subfolders
+	JobListRecursive(ctx context.Context, name JobPath) ([]JobBrief, error)
+	// MultiBranchGet returns information about multibranch pipeline or organization folder
+	MultiBranchGet(ctx context.Context, name JobPath) (*MultiBranchProject, error)
+	// MultiBranchBranches lists branches, tags and pull requests of multibranch pipeline;
+	// use BranchJobPath to address particular branch job and its builds
+	MultiBranchBranches(ctx context.Context, name JobPath) ([]Branch, error)
+	// MultiBranchScan triggers branch indexing of multibranch pipeline
+	// (or repository scanning of organization folder)
+	MultiBranchScan(ctx context.Context, name JobPath) error
+	// MultiBranchScanLog returns log of the last branch indexing (repository scanning)
+	MultiBranchScanLog(ctx context.Context, name JobPath) (string, error)
+	// JobConfigGet returns xml configuration of a job
+	JobConfigGet(ctx context.Context, name JobPath) (string, error)
+	// JobConfigUpdate overwrites xml configuration of a job
+	JobConfigUpdate(ctx context.Context, name JobPath, config string) error
+	// JobConfigUpdateIfUnchanged overwrites xml configuration of a job only if the current
+	// configuration is equal to expected one (i. e. the one obtained with JobConfigGet before)
+	JobConfigUpdateIfUnchanged(ctx context.Context, name JobPath, expected, config string) error
 	// JobExists checks wether job with a given name exists or not
-	JobExists(ctx context.Context, name string) (bool, error)
+	JobExists(ctx context.Context, name JobPath) (bool, error)
 	// JobInQueue checks whether job with a given name is in queue at the moment
-	JobInQueue(ctx context.Context, name string) (bool, error)
+	JobInQueue(ctx context.Context, name JobPath) (bool, error)
 	// JobIsBuilding checks whether job with a given name is building at the moment
-	JobIsBuilding(ctx context.Context, name string) (bool, error)
+	JobIsBuilding(ctx context.Context, name JobPath) (bool, error)
 	// BuildInvoke invokes simple (non-paramethrized) build of a given job
-	BuildInvoke(ctx context.Context, name string) (*BuildInvoked, error)
+	BuildInvoke(ctx context.Context, name JobPath) (*BuildInvoked, error)
+	// BuildInvokeWithParams invokes parametrized build of a given job
+	BuildInvokeWithParams(ctx context.Context, name JobPath, params []BuildParameter) (*BuildInvoked, error)
+	// BuildParamsValidate checks parameters against job's parameter definitions before invocation
+	BuildParamsValidate(ctx context.Context, name JobPath, params []BuildParameter) error
 	// BuildGetByNumber returns information about particular jenkins build
-	BuildGetByNumber(ctx context.Context, name string, id int) (*Build, error)
+	BuildGetByNumber(ctx context.Context, name JobPath, id int) (*Build, error)
+	// BuildGetInto requests build fields selected by receiver's type (see TreeOf) and decodes them into receiver
+	BuildGetInto(ctx context.Context, name JobPath, number int, receiver interface{}) error
 	// BuildGetByNumber returns information about particular jenkins build by given queue id
-	BuildGetByQueueID(ctx context.Context, name string, id int) (*Build, error)
+	BuildGetByQueueID(ctx context.Context, name JobPath, id int) (*Build, error)
+	// BuildHistory iterates over all the builds of a job from the newest to the oldest one;
+	// builds are requested page by page while iteration goes on
+	BuildHistory(ctx context.Context, name JobPath, opts *BuildHistoryOptions) *BuildIterator
+	// BuildConsoleText returns full console output of a build
+	BuildConsoleText(ctx context.Context, name JobPath, number int) (string, error)
+	// BuildConsoleStream follows console output of a build until it is finished;
+	// caller must close returned stream
+	BuildConsoleStream(ctx context.Context, name JobPath, number int) (io.ReadCloser, error)
+	// BuildStop aborts running build gracefully
+	BuildStop(ctx context.Context, name JobPath, number int) error
+	// BuildTerm forcibly terminates running pipeline build
+	BuildTerm(ctx context.Context, name JobPath, number int) error
+	// BuildKill hard kills running pipeline build
+	BuildKill(ctx context.Context, name JobPath, number int) error
+	// BuildAbort stops build escalating to term and kill if build keeps running;
+	// returns the step that has actually stopped the build (the last performed step on failure;
+	// term and kill are skipped for non-pipeline builds)
+	BuildAbort(ctx context.Context, name JobPath, number int, opts *AbortOptions) (BuildAbortStep, error)
+	// BuildTestReport returns results of tests recorded by JUnit plugin for a build
+	BuildTestReport(ctx context.Context, name JobPath, number int) (*TestReport, error)
+	// ArtifactDownload returns content of a file archived by a build; caller must close it.
+	// Reading the content to the end fails with ErrArtifactCorrupted if its size differs from
+	// Content-Length or its checksum differs from MD5 fingerprint recorded by Jenkins (if any)
+	ArtifactDownload(ctx context.Context, name JobPath, number int, relativePath string) (io.ReadCloser, error)
+	// ArtifactsDownloadAll stores all the build artifacts in a given directory;
+	// returns paths of written files
+	ArtifactsDownloadAll(ctx context.Context, name JobPath, number int, dir string, opts *ArtifactsDownloadOptions) ([]string, error)
+	// PipelineRunDescribe returns stages of a pipeline build
+	PipelineRunDescribe(ctx context.Context, name JobPath, number int) (*PipelineRun, error)
+	// PipelineNodeDescribe returns stage of a pipeline build with its steps
+	PipelineNodeDescribe(ctx context.Context, name JobPath, number int, nodeID string) (*PipelineStage, error)
+	// PipelineNodeLog returns console output of a pipeline step
+	PipelineNodeLog(ctx context.Context, name JobPath, number int, nodeID string) (*PipelineNodeLog, error)
+	// PipelinePendingInputs returns input steps of a pipeline build waiting for user's decision
+	PipelinePendingInputs(ctx context.Context, name JobPath, number int) ([]PipelineInput, error)
+	// PipelineInputSubmit approves input step passing parameters requested by it (if any)
+	PipelineInputSubmit(ctx context.Context, name JobPath, number int, inputID string, params []BuildParameter) error
+	// PipelineInputAbort rejects input step aborting the build
+	PipelineInputAbort(ctx context.Context, name JobPath, number int, inputID string) error
+	// BuildReplay re-runs pipeline build with modified script; loadedScripts must contain
+	// contents of all the scripts loaded by the original build (keyed by their names)
+	BuildReplay(ctx context.Context, name JobPath, number int, script string, loadedScripts map[string]string) (*BuildInvoked, error)
+	// BuildRestartFromStage re-runs completed declarative pipeline build starting from a given stage
+	BuildRestartFromStage(ctx context.Context, name JobPath, number int, stage string) (*BuildInvoked, error)
+	// PipelineValidate lints declarative Jenkinsfile with the plugins installed on controller;
+	// empty diagnostics mean that Jenkinsfile is valid
+	PipelineValidate(ctx context.Context, jenkinsfile string) ([]PipelineDiagnostic, error)
+	// WaitForBuild follows invoked build through the queue and waits until it's finished
+	WaitForBuild(ctx context.Context, invoked *BuildInvoked, opts *WaitOptions) (*Build, error)
+	// ScriptRun executes Groovy script in the script console of controller returning its output;
+	// exceptions thrown by script are returned as *ScriptError
+	ScriptRun(ctx context.Context, script string) (string, error)
+	// ScriptRunOnNode executes Groovy script on a given agent returning its output
+	ScriptRunOnNode(ctx context.Context, node, script string) (string, error)
+	// ScriptRunJSON executes Groovy script on controller and decodes its return value into out;
+	// return value is serialized with groovy.json.JsonOutput, so it should consist of maps, lists and primitives
+	ScriptRunJSON(ctx context.Context, script string, out interface{}) error
+	// GetXML requests XML API of an arbitrary route (i. e. JobPath.Route()) filtering response
+	// on server side; receiver is either encoding/xml-tagged value or *string for text results
+	GetXML(ctx context.Context, route string, query *XMLQuery, receiver interface{}) error
+	// GetJSON requests JSON API of an arbitrary route (i. e. JobPath.Route()) and decodes it into out;
+	// tree selects fields of the response (see TreeOf), nil tree requests all the fields
+	GetJSON(ctx context.Context, route string, tree *Tree, out interface{}) error
+	// Do performs request to an arbitrary route (i. e. "/scriptText") with client's credentials
+	// and CSRF crumb; contentType describes body (i. e. "application/xml"), empty one is not sent;
+	// non-2xx responses are returned as *APIError; caller must close body of returned response
+	Do(ctx context.Context, method, route string, query url.Values, contentType string, body io.Reader) (*http.Response, error)
+	// QueueList returns all items waiting in the build queue
+	QueueList(ctx context.Context) (*Queue, error)
+	// QueueItemGet returns information about particular queue item
+	QueueItemGet(ctx context.Context, id int) (*QueueItem, error)
+	// QueueItemCancel removes item from the build queue
+	QueueItemCancel(ctx context.Context, id int) error
 }
 
+// XMLQuery holds XPath filtering parameters of GetXML
+type XMLQuery = request.XMLQuery
+
 type defaultClient struct {
 	processor request.Processor
+	baseURL   *url.URL
 }
 
 func (c *defaultClient) RootInfo(ctx context.Context) (*Root, error) {
@@ -46,34 +171,36 @@ func (c *defaultClient) RootInfo(ctx context.Context) (*Root, error) {
 		Format:     request.JenkinsAPIFormatJSON,
 		DumpMethod: request.ResponseDumpDefaultJSON,
 	}
-	err := c.processor.GetJSON(apiRequest, &receiver)
-	return &receiver, err
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
 }
 
-func (c *defaultClient) JobCreate(ctx context.Context, name, config string) (*Job, error) {
+func (c *defaultClient) JobCreate(ctx context.Context, name JobPath, config string) (*Job, error) {
 	params := map[string]string{
-		"name": name,
+		"name": name.Name(),
 	}
 
 	apiRequest := &request.JenkinsAPIRequest{
 		Method:      "POST",
-		Route:       "/createItem",
+		Route:       fmt.Sprintf("%s/createItem", name.Parent().Route()),
 		Format:      request.JenkinsAPIFormatJSON,
 		Body:        strings.NewReader(config),
 		QueryParams: params,
 		DumpMethod:  request.ResponseDumpDefaultJSON,
 	}
 
-	if err := c.processor.PostXML(apiRequest, nil); err != nil {
+	if err := c.processor.PostXML(ctx, apiRequest, nil); err != nil {
 		return nil, err
 	}
 	return c.JobGet(ctx, name, 0)
 }
 
-func (c *defaultClient) JobGet(ctx context.Context, name string, depth int) (*Job, error) {
+func (c *defaultClient) JobGet(ctx context.Context, name JobPath, depth int) (*Job, error) {
 	var (
 		receiver Job
-		params   map[string]string
+		params   = make(map[string]string)
 	)
 
 	if depth > 2 {
@@ -85,39 +212,298 @@ func (c *defaultClient) JobGet(ctx context.Context, name string, depth int) (*Jo
 
 	apiRequest := &request.JenkinsAPIRequest{
 		Method:      "GET",
-		Route:       fmt.Sprintf("/job/%s", name),
+		Route:       name.Route(),
 		Format:      request.JenkinsAPIFormatJSON,
 		QueryParams: params,
 		DumpMethod:  request.ResponseDumpDefaultJSON,
 	}
-	err := c.processor.GetJSON(apiRequest, &receiver)
-	return &receiver, err
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
 }
 
-func (c *defaultClient) JobDelete(ctx context.Context, name string) error {
-	apiRequest := request.JenkinsAPIRequest{
-		Method:     "POST",
-		Route:      fmt.Sprintf("/job/%s/doDelete", name),
+func (c *defaultClient) JobGetInto(ctx context.Context, name JobPath, receiver interface{}) error {
+	return c.GetJSON(ctx, name.Route(), TreeOf(receiver), receiver)
+}
+
+func (c *defaultClient) JobDelete(ctx context.Context, name JobPath) error {
+	return c.jobAction(ctx, name, "doDelete", nil, nil)
+}
+
+func (c *defaultClient) JobCopy(ctx context.Context, from, to JobPath) (*Job, error) {
+	params := map[string]string{
+		"name": to.Name(),
+		"mode": "copy",
+		// absolute path, otherwise Jenkins looks for source job in the target folder
+		"from": "/" + strings.Join(from.Segments(), "/"),
+	}
+	if err := c.jobAction(ctx, to.Parent(), "createItem", params, nil); err != nil {
+		return nil, err
+	}
+	return c.JobGet(ctx, to, 0)
+}
+
+func (c *defaultClient) JobRename(ctx context.Context, name JobPath, newName string) (*Job, error) {
+	params := map[string]string{
+		"newName": newName,
+	}
+	if err := c.jobAction(ctx, name, "doRename", params, nil); err != nil {
+		return nil, err
+	}
+	return c.JobGet(ctx, NewJobPath(append(name.Parent().Segments(), newName)...), 0)
+}
+
+func (c *defaultClient) JobEnable(ctx context.Context, name JobPath) (*Job, error) {
+	if err := c.jobAction(ctx, name, "enable", nil, nil); err != nil {
+		return nil, err
+	}
+	return c.JobGet(ctx, name, 0)
+}
+
+func (c *defaultClient) JobDisable(ctx context.Context, name JobPath) (*Job, error) {
+	if err := c.jobAction(ctx, name, "disable", nil, nil); err != nil {
+		return nil, err
+	}
+	return c.JobGet(ctx, name, 0)
+}
+
+func (c *defaultClient) JobSetDescription(ctx context.Context, name JobPath, description string) (*Job, error) {
+	form := url.Values{
+		"description": []string{description},
+	}
+	if err := c.jobAction(ctx, name, "submitDescription", nil, form); err != nil {
+		return nil, err
+	}
+	return c.JobGet(ctx, name, 0)
+}
+
+// jobAction performs POST request to a given action of a job
+// passing optional query parameters and url-encoded form
+func (c *defaultClient) jobAction(ctx context.Context, name JobPath, action string, params map[string]string, form url.Values) error {
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "POST",
+		Route:       fmt.Sprintf("%s/%s", name.Route(), action),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpNone,
+	}
+	if form != nil {
+		apiRequest.Body = strings.NewReader(form.Encode())
+		apiRequest.ContentType = "application/x-www-form-urlencoded"
+	}
+	return c.processor.Do(ctx, apiRequest, nil)
+}
+
+func (c *defaultClient) FolderCreate(ctx context.Context, name JobPath) (*Job, error) {
+	return c.JobCreate(ctx, name, folderConfig)
+}
+
+func (c *defaultClient) FolderList(ctx context.Context, name JobPath) ([]JobBrief, error) {
+	var (
+		receiver struct {
+			Jobs []JobBrief `json:"jobs"`
+		}
+		params = map[string]string{
+			"tree": NewTree().Nested("jobs", TreeOf(JobBrief{})).String(),
+		}
+	)
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       name.Route(),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return receiver.Jobs, nil
+}
+
+// auxiliary data type for JobListRecursive request:
+// Jobs field is present only in folders' descriptions; it is selected without nested fields,
+// so Jenkins returns nothing but classes of folder's items
+type folderItem struct {
+	JobBrief
+	Jobs *[]struct{} `json:"jobs"`
+}
+
+func (c *defaultClient) JobListRecursive(ctx context.Context, name JobPath) ([]JobBrief, error) {
+	var (
+		result  []JobBrief
+		folders = []JobPath{name}
+		params  = map[string]string{
+			"tree": NewTree().Nested("jobs", TreeOf(folderItem{})).String(),
+		}
+	)
+
+	// Walk folders breadth first
+	for len(folders) != 0 {
+		var receiver struct {
+			Jobs []folderItem `json:"jobs"`
+		}
+		apiRequest := &request.JenkinsAPIRequest{
+			Method:      "GET",
+			Route:       folders[0].Route(),
+			Format:      request.JenkinsAPIFormatJSON,
+			QueryParams: params,
+			DumpMethod:  request.ResponseDumpDefaultJSON,
+		}
+		if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+			return nil, err
+		}
+		folders = folders[1:]
+
+		for _, item := range receiver.Jobs {
+			result = append(result, item.JobBrief)
+			if item.Jobs != nil {
+				folders = append(folders, item.Path())
+			}
+		}
+	}
+	return result, nil
+}
+
+func (c *defaultClient) MultiBranchGet(ctx context.Context, name JobPath) (*MultiBranchProject, error) {
+	var receiver MultiBranchProject
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      name.Route(),
 		Format:     request.JenkinsAPIFormatJSON,
+		DumpMethod: request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) MultiBranchBranches(ctx context.Context, name JobPath) ([]Branch, error) {
+	var (
+		receiver struct {
+			Jobs []Branch `json:"jobs"`
+		}
+		params = map[string]string{
+			"tree": NewTree().Nested("jobs", TreeOf(Branch{})).String(),
+		}
+	)
+
+	// 1. Request all the branch jobs
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       name.Route(),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+
+	// 2. Branch API plugin groups pull requests and tags in dedicated views
+	kinds := make(map[string]BranchKind)
+	for view, kind := range branchKindViews {
+		var viewReceiver struct {
+			Jobs []JobBrief `json:"jobs"`
+		}
+		apiRequest := &request.JenkinsAPIRequest{
+			Method:      "GET",
+			Route:       fmt.Sprintf("%s/view/%s", name.Route(), view),
+			Format:      request.JenkinsAPIFormatJSON,
+			QueryParams: map[string]string{"tree": NewTree().Nested("jobs", NewTree("name")).String()},
+			DumpMethod:  request.ResponseDumpDefaultJSON,
+		}
+		err := c.processor.GetJSON(ctx, apiRequest, &viewReceiver)
+		if errors.Is(err, ErrNotFound) {
+			// view is created only when there are pull requests or tags
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range viewReceiver.Jobs {
+			kinds[job.Name] = kind
+		}
+	}
+
+	for i := range receiver.Jobs {
+		receiver.Jobs[i].Kind = kinds[receiver.Jobs[i].Name]
+	}
+	return receiver.Jobs, nil
+}
+
+func (c *defaultClient) MultiBranchScan(ctx context.Context, name JobPath) error {
+	params := map[string]string{
+		"delay": "0",
+	}
+	return c.jobAction(ctx, name, "build", params, nil)
+}
+
+func (c *defaultClient) MultiBranchScanLog(ctx context.Context, name JobPath) (string, error) {
+	project, err := c.MultiBranchGet(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return c.rawGetText(ctx, fmt.Sprintf("%s/consoleText", project.scanRoute()))
+}
+
+func (c *defaultClient) JobConfigGet(ctx context.Context, name JobPath) (string, error) {
+	return c.rawGetText(ctx, fmt.Sprintf("%s/config.xml", name.Route()))
+}
+
+func (c *defaultClient) JobConfigUpdate(ctx context.Context, name JobPath, config string) error {
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "POST",
+		Route:      fmt.Sprintf("%s/config.xml", name.Route()),
+		Format:     request.JenkinsAPIFormatNone,
+		Body:       strings.NewReader(config),
 		DumpMethod: request.ResponseDumpNone,
 	}
-	return c.processor.Post(&apiRequest, nil)
+	return c.processor.PostXML(ctx, apiRequest, nil)
 }
 
-func (c *defaultClient) JobExists(ctx context.Context, name string) (bool, error) {
-	info, err := c.RootInfo(ctx)
+// Jenkins doesn't support conditional requests for config.xml, so there is still a short
+// window between comparison and update, but concurrent automation runs won't silently
+// overwrite each other's changes made between JobConfigGet and JobConfigUpdateIfUnchanged
+func (c *defaultClient) JobConfigUpdateIfUnchanged(ctx context.Context, name JobPath, expected, config string) error {
+	current, err := c.JobConfigGet(ctx, name)
 	if err != nil {
-		return false, err
+		return err
 	}
-	for _, job := range info.Jobs {
-		if job.Name == name {
-			return true, nil
+	if current != expected {
+		return fmt.Errorf("Job %s: %w", name, ErrJobConfigChanged)
+	}
+	return c.JobConfigUpdate(ctx, name, config)
+}
+
+func (c *defaultClient) JobExists(ctx context.Context, name JobPath) (bool, error) {
+	var (
+		receiver JobBrief
+		params   = map[string]string{
+			"tree": NewTree("name").String(),
 		}
+	)
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       name.Route(),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpDefaultJSON,
+	}
+	err := c.processor.GetJSON(ctx, apiRequest, &receiver)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
 	}
-	return false, nil
 }
 
-func (c *defaultClient) JobInQueue(ctx context.Context, name string) (bool, error) {
+func (c *defaultClient) JobInQueue(ctx context.Context, name JobPath) (bool, error) {
 	job, err := c.JobGet(ctx, name, 0)
 	if err != nil {
 		return false, err
@@ -125,7 +511,7 @@ func (c *defaultClient) JobInQueue(ctx context.Context, name string) (bool, erro
 	return job.InQueue, nil
 }
 
-func (c *defaultClient) JobIsBuilding(ctx context.Context, name string) (bool, error) {
+func (c *defaultClient) JobIsBuilding(ctx context.Context, name JobPath) (bool, error) {
 	job, err := c.JobGet(ctx, name, 0)
 	if err != nil {
 		return false, err
@@ -133,84 +519,800 @@ func (c *defaultClient) JobIsBuilding(ctx context.Context, name string) (bool, e
 	return job.LastBuild.Building, nil
 }
 
-func (c *defaultClient) BuildInvoke(ctx context.Context, name string) (*BuildInvoked, error) {
+func (c *defaultClient) BuildInvoke(ctx context.Context, name JobPath) (*BuildInvoked, error) {
 	var receiver url.URL
 	apiRequest := &request.JenkinsAPIRequest{
 		Method:     "POST",
-		Route:      fmt.Sprintf("/job/%s/build", name),
+		Route:      fmt.Sprintf("%s/build", name.Route()),
 		Format:     request.JenkinsAPIFormatJSON,
 		DumpMethod: request.ResponseDumpHeaderLocation,
 	}
-	if err := c.processor.Post(apiRequest, &receiver); err != nil {
+	if err := c.processor.Do(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return NewBuildInvokedFromURL(&receiver)
+}
+
+func (c *defaultClient) BuildInvokeWithParams(ctx context.Context, name JobPath, params []BuildParameter) (*BuildInvoked, error) {
+	body, contentType, err := encodeBuildParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var receiver url.URL
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "POST",
+		Route:       fmt.Sprintf("%s/buildWithParameters", name.Route()),
+		Format:      request.JenkinsAPIFormatJSON,
+		Body:        body,
+		ContentType: contentType,
+		DumpMethod:  request.ResponseDumpHeaderLocation,
+	}
+	if err := c.processor.Do(ctx, apiRequest, &receiver); err != nil {
 		return nil, err
 	}
 	return NewBuildInvokedFromURL(&receiver)
 }
 
-func (c *defaultClient) BuildGetByNumber(ctx context.Context, name string, buildID int) (*Build, error) {
+func (c *defaultClient) BuildParamsValidate(ctx context.Context, name JobPath, params []BuildParameter) error {
+	job, err := c.JobGet(ctx, name, 0)
+	if err != nil {
+		return err
+	}
+	return ValidateBuildParameters(job, params)
+}
+
+func (c *defaultClient) BuildGetByNumber(ctx context.Context, name JobPath, buildID int) (*Build, error) {
+	return c.buildGetByRoute(ctx, fmt.Sprintf("%s/%d", name.Route(), buildID))
+}
+
+func (c *defaultClient) BuildGetInto(ctx context.Context, name JobPath, number int, receiver interface{}) error {
+	return c.GetJSON(ctx, fmt.Sprintf("%s/%d", name.Route(), number), TreeOf(receiver), receiver)
+}
+
+func (c *defaultClient) buildGetByRoute(ctx context.Context, route string) (*Build, error) {
 	var receiver Build
 	apiRequest := &request.JenkinsAPIRequest{
 		Method:      "GET",
-		Route:       fmt.Sprintf("/job/%s/%d", name, buildID),
+		Route:       route,
 		Format:      request.JenkinsAPIFormatJSON,
 		Body:        nil,
 		QueryParams: nil,
 		DumpMethod:  request.ResponseDumpDefaultJSON,
 	}
-	err := c.processor.GetJSON(apiRequest, &receiver)
-	return &receiver, err
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
 }
 
-// auxiliary data types for BuildGetByQueueID request
-type build struct {
-	BuildID string `json:"id"`
-	QueueID int    `json:"queueId"`
-	URL     string `json:"url"`
+func (c *defaultClient) BuildGetByQueueID(ctx context.Context, name JobPath, queueID int) (*Build, error) {
+	// 1. Ask queue which build has been started from the item
+	item, err := c.QueueItemGet(ctx, queueID)
+	if errors.Is(err, ErrNotFound) {
+		// Jenkins forgets queue items in a few minutes after they leave the queue
+		return c.buildGetByQueueIDFromHistory(ctx, name, queueID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Make sure that queue item belongs to the requested job
+	taskPath, err := jobPathFromURL(item.Task.URL)
+	if err != nil {
+		return nil, err
+	}
+	if taskPath.Route() != name.Route() {
+		return nil, fmt.Errorf("Queue item %d belongs to %s, not to job %s: %w", queueID, item.Task.Name, name, ErrNotFound)
+	}
+
+	switch {
+	case item.Cancelled:
+		return nil, fmt.Errorf("Queue item %d: %w", queueID, ErrQueueItemCancelled)
+	case item.Executable == nil:
+		return nil, fmt.Errorf("Build for a job %s with a queueID %d has not been started yet: %w", name, queueID, ErrNotFound)
+	}
+
+	// 3. Get build
+	buildRoute, err := c.routeFromURL(item.Executable.URL)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildGetByRoute(ctx, buildRoute)
+}
+
+// buildGetByQueueIDFromHistory scans build history of a job for a build with a given queueID
+func (c *defaultClient) buildGetByQueueIDFromHistory(ctx context.Context, name JobPath, queueID int) (*Build, error) {
+	// 1. Walk through brief build descriptions of a particular job
+	history := c.BuildHistory(ctx, name, &BuildHistoryOptions{Fields: "number,queueId"})
+	for history.Next() {
+		if build := history.Build(); build.QueueID == queueID {
+			// 2. Get build
+			return c.BuildGetByNumber(ctx, name, build.Number)
+		}
+	}
+	if err := history.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("Build for a job %s with a queueID %d was not found: %w", name, queueID, ErrNotFound)
 }
-type buildList struct {
-	Builds []*build `json:"builds"`
+
+func (c *defaultClient) BuildHistory(ctx context.Context, name JobPath, opts *BuildHistoryOptions) *BuildIterator {
+	return &BuildIterator{ctx: ctx, client: c, name: name, opts: opts.withDefaults()}
+}
+
+func (c *defaultClient) BuildConsoleText(ctx context.Context, name JobPath, number int) (string, error) {
+	return c.rawGetText(ctx, fmt.Sprintf("%s/%d/consoleText", name.Route(), number))
+}
+
+func (c *defaultClient) BuildConsoleStream(ctx context.Context, name JobPath, number int) (io.ReadCloser, error) {
+	route := fmt.Sprintf("%s/%d/logText/progressiveText", name.Route(), number)
+
+	// The first request is performed synchronously to report missing builds immediately
+	chunk, err := c.buildConsoleChunk(ctx, route, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	reader, writer := io.Pipe()
+	go func() {
+		var err error
+		for {
+			_, err = io.Copy(writer, chunk.response.Body)
+			chunk.response.Body.Close()
+			if err != nil || !chunk.moreData {
+				break
+			}
+			if err = sleepContext(ctx, consolePollInterval); err != nil {
+				break
+			}
+			if chunk, err = c.buildConsoleChunk(ctx, route, chunk.textSize); err != nil {
+				break
+			}
+		}
+		writer.CloseWithError(err)
+	}()
+
+	return &consoleStream{PipeReader: reader, cancel: cancel}, nil
 }
 
-func (c *defaultClient) BuildGetByQueueID(ctx context.Context, name string, queueID int) (*Build, error) {
-	// 1. Request list of brief build descriptions of a particular job
+func (c *defaultClient) buildConsoleChunk(ctx context.Context, route string, start int64) (*consoleChunk, error) {
 	var (
-		receiver buildList
+		receiver http.Response
 		params   = map[string]string{
-			"tree": "builds[id,queueId,url]",
+			"start": strconv.FormatInt(start, 10),
 		}
 	)
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       route,
+		Format:      request.JenkinsAPIFormatNone,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpRaw,
+	}
+	if err := c.processor.Do(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return newConsoleChunk(&receiver, start)
+}
+
+func (c *defaultClient) BuildStop(ctx context.Context, name JobPath, number int) error {
+	return c.buildAction(ctx, name, number, "stop")
+}
+
+func (c *defaultClient) BuildTerm(ctx context.Context, name JobPath, number int) error {
+	return c.buildAction(ctx, name, number, "term")
+}
+
+func (c *defaultClient) BuildKill(ctx context.Context, name JobPath, number int) error {
+	return c.buildAction(ctx, name, number, "kill")
+}
+
+func (c *defaultClient) buildAction(ctx context.Context, name JobPath, number int, action string) error {
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "POST",
+		Route:      fmt.Sprintf("%s/%d/%s", name.Route(), number, action),
+		Format:     request.JenkinsAPIFormatJSON,
+		DumpMethod: request.ResponseDumpNone,
+	}
+	return c.processor.Do(ctx, apiRequest, nil)
+}
+
+func (c *defaultClient) BuildAbort(ctx context.Context, name JobPath, number int, opts *AbortOptions) (BuildAbortStep, error) {
+	opts = opts.withDefaults()
+
+	build, err := c.BuildGetByNumber(ctx, name, number)
+	if err != nil {
+		return BuildAbortNone, err
+	}
+	if !build.Building {
+		return BuildAbortNone, nil
+	}
+
+	// term and kill are supported by pipeline builds only
+	steps := []struct {
+		step     BuildAbortStep
+		action   func(context.Context, JobPath, int) error
+		timeout  time.Duration
+		optional bool
+	}{
+		{BuildAbortStop, c.BuildStop, opts.StopTimeout, false},
+		{BuildAbortTerm, c.BuildTerm, opts.TermTimeout, true},
+		{BuildAbortKill, c.BuildKill, opts.KillTimeout, true},
+	}
+	performed := BuildAbortNone
+	for _, step := range steps {
+		err = step.action(ctx, name, number)
+		if step.optional && errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return performed, err
+		}
+		performed = step.step
+
+		deadline := time.Now().Add(step.timeout)
+		for time.Now().Before(deadline) {
+			if err = sleepContext(ctx, opts.Interval); err != nil {
+				return performed, err
+			}
+			if build, err = c.BuildGetByNumber(ctx, name, number); err != nil {
+				return performed, err
+			}
+			if !build.Building {
+				return performed, nil
+			}
+		}
+	}
+	return performed, fmt.Errorf("Build %s #%d: %w", name, number, ErrBuildNotAborted)
+}
+
+func (c *defaultClient) BuildTestReport(ctx context.Context, name JobPath, number int) (*TestReport, error) {
+	var receiver TestReport
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      fmt.Sprintf("%s/%d/testReport", name.Route(), number),
+		Format:     request.JenkinsAPIFormatJSON,
+		DumpMethod: request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) ArtifactDownload(ctx context.Context, name JobPath, number int, relativePath string) (io.ReadCloser, error) {
+	hashes, err := c.artifactHashes(ctx, name, number)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.rawGet(ctx, artifactRoute(name, number, relativePath))
+	if err != nil {
+		return nil, err
+	}
+	return newArtifactReader(response.Body, relativePath, response.ContentLength, artifactHash(hashes, relativePath)), nil
+}
+
+func (c *defaultClient) ArtifactsDownloadAll(
+	ctx context.Context,
+	name JobPath,
+	number int,
+	dir string,
+	opts *ArtifactsDownloadOptions,
+) ([]string, error) {
+	if opts == nil {
+		opts = &ArtifactsDownloadOptions{}
+	}
+
+	// 1. Request checksums of files if necessary
+	var hashes map[string]string
+	if opts.VerifyChecksum {
+		var err error
+		if hashes, err = c.artifactHashes(ctx, name, number); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Download all artifacts at once
+	if opts.Zip {
+		return c.artifactsDownloadZip(ctx, name, number, dir, hashes)
+	}
+
+	// 3. Download artifacts one by one
+	build, err := c.BuildGetByNumber(ctx, name, number)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, artifact := range build.Artifacts {
+		localPath, err := artifactLocalPath(dir, artifact.RelativePath)
+		if err != nil {
+			return paths, err
+		}
+		response, err := c.rawGet(ctx, artifactRoute(name, number, artifact.RelativePath))
+		if err != nil {
+			return paths, err
+		}
+		writer := newArtifactWriter(localPath)
+		err = writer.writeFrom(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return paths, err
+		}
+		if err = writer.verify(response.ContentLength, artifactHash(hashes, artifact.RelativePath)); err != nil {
+			return paths, err
+		}
+		paths = append(paths, localPath)
+	}
+	return paths, nil
+}
 
+// artifactHashes requests MD5 fingerprints of build artifacts mapped by their paths
+func (c *defaultClient) artifactHashes(ctx context.Context, name JobPath, number int) (map[string]string, error) {
+	var receiver struct {
+		Fingerprint []BuildFingerprint `json:"fingerprint"`
+	}
 	apiRequest := &request.JenkinsAPIRequest{
 		Method:      "GET",
-		Route:       fmt.Sprintf("/job/%s", name),
+		Route:       fmt.Sprintf("%s/%d", name.Route(), number),
 		Format:      request.JenkinsAPIFormatJSON,
-		Body:        nil,
-		QueryParams: params,
+		QueryParams: map[string]string{"tree": NewTree().Nested("fingerprint", TreeOf(BuildFingerprint{})).String()},
 		DumpMethod:  request.ResponseDumpDefaultJSON,
 	}
-	if err := c.processor.GetJSON(apiRequest, &receiver); err != nil {
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
 		return nil, err
 	}
+	return fingerprintsByPath(receiver.Fingerprint), nil
+}
 
-	// 2. Search for a job with a particular queueID
-	var (
-		err     error
-		buildID int
-	)
-	for _, item := range receiver.Builds {
-		if queueID == item.QueueID {
-			if buildID, err = strconv.Atoi(item.BuildID); err != nil {
-				return nil, err
-			}
+func (c *defaultClient) artifactsDownloadZip(ctx context.Context, name JobPath, number int, dir string, hashes map[string]string) ([]string, error) {
+	response, err := c.rawGet(ctx, fmt.Sprintf("%s/%d/artifact/*zip*/archive.zip", name.Route(), number))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// Archive is stored on disk because zip cannot be read sequentially
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	archive, err := ioutil.TempFile(dir, ".archive-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(archive.Name())
+
+	_, err = io.Copy(archive, response.Body)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return extractArtifactsArchive(archive.Name(), dir, hashes)
+}
+
+// rawGetText requests arbitrary route returning response body as a string
+func (c *defaultClient) rawGetText(ctx context.Context, route string) (string, error) {
+	response, err := c.rawGet(ctx, route)
+	if err != nil {
+		return "", err
+	}
+	return readResponseText(response)
+}
+
+// rawPostText submits form to arbitrary route returning response body as a string
+func (c *defaultClient) rawPostText(ctx context.Context, route string, form url.Values) (string, error) {
+	response, err := c.Do(ctx, "POST", route, nil, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	return readResponseText(response)
+}
+
+// readResponseText reads and closes response body
+func readResponseText(response *http.Response) (string, error) {
+	defer response.Body.Close()
+
+	text, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// rawGet requests arbitrary route returning response with unread body
+func (c *defaultClient) rawGet(ctx context.Context, route string) (*http.Response, error) {
+	return c.Do(ctx, "GET", route, nil, "", nil)
+}
+
+func (c *defaultClient) PipelineRunDescribe(ctx context.Context, name JobPath, number int) (*PipelineRun, error) {
+	var receiver PipelineRun
+	route := fmt.Sprintf("%s/%d/wfapi/describe", name.Route(), number)
+	if err := c.wfapiGet(ctx, route, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) PipelineNodeDescribe(ctx context.Context, name JobPath, number int, nodeID string) (*PipelineStage, error) {
+	var receiver PipelineStage
+	route := fmt.Sprintf("%s/%d/execution/node/%s/wfapi/describe", name.Route(), number, url.PathEscape(nodeID))
+	if err := c.wfapiGet(ctx, route, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) PipelineNodeLog(ctx context.Context, name JobPath, number int, nodeID string) (*PipelineNodeLog, error) {
+	var receiver PipelineNodeLog
+	route := fmt.Sprintf("%s/%d/execution/node/%s/wfapi/log", name.Route(), number, url.PathEscape(nodeID))
+	if err := c.wfapiGet(ctx, route, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) PipelinePendingInputs(ctx context.Context, name JobPath, number int) ([]PipelineInput, error) {
+	var receiver []PipelineInput
+	route := fmt.Sprintf("%s/%d/wfapi/pendingInputActions", name.Route(), number)
+	if err := c.wfapiGet(ctx, route, &receiver); err != nil {
+		return nil, err
+	}
+	return receiver, nil
+}
+
+func (c *defaultClient) PipelineInputSubmit(ctx context.Context, name JobPath, number int, inputID string, params []BuildParameter) error {
+	if len(params) == 0 {
+		return c.jobAction(ctx, name, fmt.Sprintf("%d/input/%s/proceedEmpty", number, url.PathEscape(inputID)), nil, nil)
+	}
+	form, err := encodeInputParameters("Proceed", params)
+	if err != nil {
+		return err
+	}
+	return c.jobAction(ctx, name, fmt.Sprintf("%d/input/%s/submit", number, url.PathEscape(inputID)), nil, form)
+}
+
+func (c *defaultClient) PipelineInputAbort(ctx context.Context, name JobPath, number int, inputID string) error {
+	return c.jobAction(ctx, name, fmt.Sprintf("%d/input/%s/abort", number, url.PathEscape(inputID)), nil, nil)
+}
+
+func (c *defaultClient) BuildReplay(
+	ctx context.Context,
+	name JobPath,
+	number int,
+	script string,
+	loadedScripts map[string]string,
+) (*BuildInvoked, error) {
+	form := map[string]string{"mainScript": script}
+	for scriptName, content := range loadedScripts {
+		// Jenkins expects dots in the names of loaded scripts to be replaced with underscores
+		form[strings.Replace(scriptName, ".", "_", -1)] = content
+	}
+	return c.buildRerun(ctx, name, number, "replay/run", form, newRerunCause(ReplayCauseClass, number))
+}
+
+func (c *defaultClient) BuildRestartFromStage(ctx context.Context, name JobPath, number int, stage string) (*BuildInvoked, error) {
+	form := map[string]string{"stageName": stage}
+	return c.buildRerun(ctx, name, number, "restart/restart", form, newRerunCause(RestartCauseClass, number))
+}
+
+// buildRerun submits structured form to a build action and looks for the queue item created by it
+func (c *defaultClient) buildRerun(
+	ctx context.Context,
+	name JobPath,
+	number int,
+	action string,
+	form map[string]string,
+	cause *rerunCause,
+) (*BuildInvoked, error) {
+	// 1. Remember next build number to tell the new build from the older ones with the same cause
+	var job struct {
+		NextBuildNumber int `json:"nextBuildNumber"`
+	}
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       name.Route(),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: map[string]string{"tree": NewTree("nextBuildNumber").String()},
+		DumpMethod:  request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &job); err != nil {
+		return nil, err
+	}
+
+	// 2. Submit the form
+	encoded, err := json.Marshal(form)
+	if err != nil {
+		return nil, err
+	}
+	apiRequest = &request.JenkinsAPIRequest{
+		Method:      "POST",
+		Route:       fmt.Sprintf("%s/%d/%s", name.Route(), number, action),
+		Format:      request.JenkinsAPIFormatNone,
+		Body:        strings.NewReader(url.Values{"json": []string{string(encoded)}}.Encode()),
+		ContentType: "application/x-www-form-urlencoded",
+		DumpMethod:  request.ResponseDumpNone,
+	}
+	if err = c.processor.Do(ctx, apiRequest, nil); err != nil {
+		return nil, err
+	}
+
+	// 3. Look for the queue item in the queue and in the build history
+	for attempt := 1; ; attempt++ {
+		queueID, err := c.rerunQueueID(ctx, name, cause, job.NextBuildNumber)
+		if err != nil {
+			return nil, err
+		}
+		if queueID != 0 {
+			queueURL := *c.baseURL
+			queueURL.Path = fmt.Sprintf("%s/queue/item/%d/", strings.TrimSuffix(c.baseURL.Path, "/"), queueID)
+			queueURL.RawPath = ""
+			return NewBuildInvokedFromURL(&queueURL)
+		}
+		if attempt == rerunLookupAttempts {
+			return nil, fmt.Errorf("Queue item created by %s of %s #%d was not found: %w", action, name, number, ErrNotFound)
+		}
+		if err = sleepContext(ctx, rerunLookupInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rerunQueueID returns ID of the latest queue item of a job with a given cause;
+// build history is checked starting from a given build number, since the item may leave the queue immediately
+func (c *defaultClient) rerunQueueID(ctx context.Context, name JobPath, cause *rerunCause, since int) (int, error) {
+	queue, err := c.QueueList(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var queueID int
+	for _, item := range queue.Filter(QueueFilterByJob(name), cause.queueFilter()) {
+		if item.ID > queueID {
+			queueID = item.ID
+		}
+	}
+	if queueID != 0 {
+		return queueID, nil
+	}
+
+	var receiver struct {
+		Builds []rerunBuild `json:"builds"`
+	}
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       name.Route(),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: map[string]string{"tree": NewTree().NestedRange("builds", rerunBuildTree, 0, 10).String()},
+		DumpMethod:  request.ResponseDumpDefaultJSON,
+	}
+	if err = c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return 0, err
+	}
+	for _, build := range receiver.Builds {
+		if build.Number >= since && build.QueueID > queueID && cause.matches(build.Actions) {
+			queueID = build.QueueID
+		}
+	}
+	return queueID, nil
+}
+
+func (c *defaultClient) PipelineValidate(ctx context.Context, jenkinsfile string) ([]PipelineDiagnostic, error) {
+	form := url.Values{"jenkinsfile": []string{jenkinsfile}}
+	output, err := c.rawPostText(ctx, "/pipeline-model-converter/validate", form)
+	if err != nil {
+		return nil, err
+	}
+	return parsePipelineValidation(output), nil
+}
+
+// wfapiGet requests Pipeline Stage View plugin REST API which lives outside of /api/json
+func (c *defaultClient) wfapiGet(ctx context.Context, route string, receiver interface{}) error {
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      route,
+		Format:     request.JenkinsAPIFormatNone,
+		DumpMethod: request.ResponseDumpDefaultJSON,
+	}
+	return c.processor.GetJSON(ctx, apiRequest, receiver)
+}
+
+func (c *defaultClient) WaitForBuild(ctx context.Context, invoked *BuildInvoked, opts *WaitOptions) (*Build, error) {
+	opts = opts.withDefaults()
+	interval := opts.Interval
+
+	// 1. Poll queue item until the build will be started
+	var executable *QueueItemExecutable
+	for {
+		item, err := c.QueueItemGet(ctx, invoked.ID)
+		if err != nil {
+			return nil, err
+		}
+		if item.Cancelled {
+			return nil, fmt.Errorf("Queue item %d: %w", invoked.ID, ErrQueueItemCancelled)
+		}
+		if item.Executable != nil {
+			executable = item.Executable
 			break
 		}
+		if opts.OnQueued != nil {
+			opts.OnQueued(item)
+		}
+		if err = sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval = opts.nextInterval(interval)
+	}
+
+	// 2. Poll build until it will be finished
+	route, err := c.routeFromURL(executable.URL)
+	if err != nil {
+		return nil, err
+	}
+	interval = opts.Interval
+	for {
+		build, err := c.buildGetByRoute(ctx, route)
+		if err != nil {
+			return nil, err
+		}
+		if !build.Building {
+			return build, nil
+		}
+		if opts.OnBuilding != nil {
+			opts.OnBuilding(build)
+		}
+		if err = sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval = opts.nextInterval(interval)
 	}
-	if buildID == 0 {
-		return nil, fmt.Errorf("Build for a job %s with a queueID %d was not found", name, queueID)
+}
+
+func (c *defaultClient) ScriptRun(ctx context.Context, script string) (string, error) {
+	output, _, err := c.scriptRun(ctx, "/scriptText", script, false)
+	return output, err
+}
+
+func (c *defaultClient) ScriptRunOnNode(ctx context.Context, node, script string) (string, error) {
+	output, _, err := c.scriptRun(ctx, fmt.Sprintf("/computer/%s/scriptText", url.PathEscape(node)), script, false)
+	return output, err
+}
+
+func (c *defaultClient) ScriptRunJSON(ctx context.Context, script string, out interface{}) error {
+	_, result, err := c.scriptRun(ctx, "/scriptText", script, true)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal([]byte(result), out); err != nil {
+		return fmt.Errorf("Cannot decode script result %q: %w", result, err)
 	}
+	return nil
+}
 
-	// 3. Get build
-	return c.BuildGetByNumber(ctx, name, buildID)
+// scriptRun executes wrapped script returning its output and serialized return value (if requested)
+func (c *defaultClient) scriptRun(ctx context.Context, route, script string, withResult bool) (string, string, error) {
+	form := url.Values{"script": []string{wrapScript(script, withResult)}}
+	output, err := c.rawPostText(ctx, route, form)
+	if err != nil {
+		return "", "", err
+	}
+	return parseScriptOutput(output)
+}
+
+func (c *defaultClient) GetXML(ctx context.Context, route string, query *XMLQuery, receiver interface{}) error {
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      route,
+		Format:     request.JenkinsAPIFormatXML,
+		XMLQuery:   query,
+		DumpMethod: request.ResponseDumpDefaultXML,
+	}
+	return c.processor.Do(ctx, apiRequest, receiver)
+}
+
+func (c *defaultClient) GetJSON(ctx context.Context, route string, tree *Tree, out interface{}) error {
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      route,
+		Format:     request.JenkinsAPIFormatJSON,
+		DumpMethod: request.ResponseDumpDefaultJSON,
+	}
+	if tree != nil {
+		apiRequest.QueryParams = map[string]string{"tree": tree.String()}
+	}
+	return c.processor.GetJSON(ctx, apiRequest, out)
+}
+
+func (c *defaultClient) Do(
+	ctx context.Context,
+	method, route string,
+	query url.Values,
+	contentType string,
+	body io.Reader,
+) (*http.Response, error) {
+	var receiver http.Response
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      method,
+		Route:       route,
+		Format:      request.JenkinsAPIFormatNone,
+		Body:        body,
+		Query:       query,
+		ContentType: contentType,
+		DumpMethod:  request.ResponseDumpRaw,
+	}
+	if err := c.processor.Do(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) QueueList(ctx context.Context) (*Queue, error) {
+	var receiver Queue
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      "/queue",
+		Format:     request.JenkinsAPIFormatJSON,
+		DumpMethod: request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) QueueItemGet(ctx context.Context, id int) (*QueueItem, error) {
+	var receiver QueueItem
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:     "GET",
+		Route:      fmt.Sprintf("/queue/item/%d", id),
+		Format:     request.JenkinsAPIFormatJSON,
+		DumpMethod: request.ResponseDumpDefaultJSON,
+	}
+	if err := c.processor.GetJSON(ctx, apiRequest, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+func (c *defaultClient) QueueItemCancel(ctx context.Context, id int) error {
+	params := map[string]string{
+		"id": strconv.Itoa(id),
+	}
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "POST",
+		Route:       "/queue/cancelItem",
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpNone,
+	}
+	return c.processor.Do(ctx, apiRequest, nil)
+}
+
+// routeFromURL converts absolute URL returned by Jenkins API
+// into the route relative to the client's base URL
+func (c *defaultClient) routeFromURL(absoluteURL string) (string, error) {
+	parsed, err := url.Parse(absoluteURL)
+	if err != nil {
+		return "", err
+	}
+	basePath := strings.TrimSuffix(c.baseURL.EscapedPath(), "/")
+	if !strings.HasPrefix(parsed.EscapedPath(), basePath) {
+		return "", fmt.Errorf("URL %s doesn't belong to %s", absoluteURL, c.baseURL)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(parsed.EscapedPath(), basePath), "/"), nil
+}
+
+// sleepContext pauses current goroutine for a given duration or until context is done
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // PluginInstall performs installation of latest version of the plugin to Jenkins server;
@@ -227,16 +1329,21 @@ func (c *defaultClient) PluginInstall(ctx context.Context, name string) error {
 		QueryParams: nil,
 		DumpMethod:  request.ResponseDumpNone,
 	}
-	return c.processor.Post(&apiRequest, nil)
+	return c.processor.Do(ctx, &apiRequest, nil)
 }
 
 // NewJenkins initialises an entrypoint for Jenkins API
 func NewClient(baseURL string, username string, password string, debug bool) (Client, error) {
 
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
 	processor, err := request.NewProcessor(baseURL, username, password, debug)
 	if err != nil {
 		return nil, err
 	}
 
-	return &defaultClient{processor: processor}, nil
+	return &defaultClient{processor: processor, baseURL: parsedURL}, nil
 }
@@ -0,0 +1,50 @@
+package jenkins
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// consolePollInterval is a delay between two subsequent requests for the new console output
+const consolePollInterval = time.Second
+
+// consoleStream yields console output of a running build;
+// closing it stops polling Jenkins
+type consoleStream struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (s *consoleStream) Close() error {
+	s.cancel()
+	return s.PipeReader.Close()
+}
+
+// consoleChunk is a piece of console output returned by progressiveText endpoint
+type consoleChunk struct {
+	response *http.Response
+	// textSize is an offset to request the next chunk from
+	textSize int64
+	// moreData is true while the build is running
+	moreData bool
+}
+
+func newConsoleChunk(response *http.Response, start int64) (*consoleChunk, error) {
+	chunk := &consoleChunk{
+		response: response,
+		textSize: start,
+		moreData: response.Header.Get("X-More-Data") == "true",
+	}
+	if textSize := response.Header.Get("X-Text-Size"); textSize != "" {
+		parsed, err := strconv.ParseInt(textSize, 10, 64)
+		if err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+		chunk.textSize = parsed
+	}
+	return chunk, nil
+}
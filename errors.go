@@ -0,0 +1,34 @@
+package jenkins
+
+import (
+	"errors"
+
+	"github.com/vitalyisaev2/jenkins-client-golang/request"
+)
+
+// APIError describes unexpected HTTP response from Jenkins API;
+// use errors.As to obtain status code, method, URL and response body snippet
+type APIError = request.APIError
+
+// Sentinel errors returned (wrapped) by Client methods; use errors.Is to check them
+var (
+	ErrBadRequest   = request.ErrBadRequest
+	ErrUnauthorized = request.ErrUnauthorized
+	ErrForbidden    = request.ErrForbidden
+	ErrCrumbInvalid = request.ErrCrumbInvalid
+	ErrNotFound     = request.ErrNotFound
+	ErrConflict     = request.ErrConflict
+)
+
+var (
+	// ErrQueueItemCancelled is returned when awaited queue item has been cancelled before the build was started
+	ErrQueueItemCancelled = errors.New("jenkins: queue item cancelled")
+	// ErrBuildNotAborted is returned when build keeps running after all the abortion attempts
+	ErrBuildNotAborted = errors.New("jenkins: build has not been aborted")
+	// ErrArtifactCorrupted is returned when downloaded artifact doesn't match expected size or checksum
+	ErrArtifactCorrupted = errors.New("jenkins: artifact corrupted")
+	// ErrJobConfigChanged is returned when job configuration has been modified concurrently
+	ErrJobConfigChanged = errors.New("jenkins: job configuration has been changed concurrently")
+	// ErrScriptFailed is returned (wrapped into *ScriptError) when Groovy script throws an exception
+	ErrScriptFailed = errors.New("jenkins: script failed")
+)
@@ -0,0 +1,181 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vitalyisaev2/jenkins-client-golang/request"
+)
+
+// Defaults of BuildHistoryOptions
+const (
+	defaultBuildHistoryPageSize = 100
+	defaultBuildHistoryFields   = "number,url,result,building,timestamp,duration,builtOn,queueId," +
+		"actions[causes[_class,shortDescription,userId,userName]]"
+)
+
+// BuildHistoryOptions configure BuildHistory
+type BuildHistoryOptions struct {
+	// PageSize is a number of builds requested at once (100 by default)
+	PageSize int
+	// Fields is a tree expression selecting fields of builds ("number,result" or TreeOf(Build{}).String()
+	// for instance); it must include fields used by filters, except for "timestamp" which is added
+	// automatically when Since or Until is set
+	Fields string
+	// Since and Until limit the time range of build start; zero values mean no limit
+	Since time.Time
+	Until time.Time
+	// Filters select builds to be returned
+	Filters []BuildFilter
+}
+
+func (o *BuildHistoryOptions) withDefaults() *BuildHistoryOptions {
+	var result BuildHistoryOptions
+	if o != nil {
+		result = *o
+	}
+	if result.PageSize <= 0 {
+		result.PageSize = defaultBuildHistoryPageSize
+	}
+	if result.Fields == "" {
+		result.Fields = defaultBuildHistoryFields
+	}
+	if (!result.Since.IsZero() || !result.Until.IsZero()) && !treeExpressionSelects(result.Fields, "timestamp") {
+		result.Fields += ",timestamp"
+	}
+	return &result
+}
+
+// BuildFilter is a predicate used to select builds
+type BuildFilter func(*Build) bool
+
+// BuildFilterByResult selects finished builds with any of the given results ("SUCCESS", "FAILURE" etc.)
+func BuildFilterByResult(results ...string) BuildFilter {
+	return func(build *Build) bool {
+		for _, result := range results {
+			if build.Result == result {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// BuildFilterBuiltOn selects builds performed on a given node (empty name stands for built-in node)
+func BuildFilterBuiltOn(node string) BuildFilter {
+	return func(build *Build) bool {
+		return build.BuiltOn == node
+	}
+}
+
+// BuildFilterByCause selects builds having cause of a given class
+// ("hudson.model.Cause$UserIdCause", "hudson.triggers.TimerTrigger$TimerTriggerCause" etc.)
+func BuildFilterByCause(class string) BuildFilter {
+	return func(build *Build) bool {
+		for _, action := range build.Actions {
+			for _, cause := range action.Causes {
+				if cause["_class"] == class {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// BuildIterator walks through build history from the newest builds to the oldest ones
+// requesting pages of builds lazily:
+//
+//	history := client.BuildHistory(ctx, name, nil)
+//	for history.Next() {
+//		build := history.Build()
+//	}
+//	if err := history.Err(); err != nil {
+//		...
+//	}
+type BuildIterator struct {
+	ctx    context.Context
+	client *defaultClient
+	name   JobPath
+	opts   *BuildHistoryOptions
+	page   []Build
+	offset int
+	build  *Build
+	done   bool
+	err    error
+}
+
+// Next advances iterator to the next build matching filters; returns false when
+// the history is over or an error has occurred
+func (it *BuildIterator) Next() bool {
+	for it.err == nil {
+		if len(it.page) == 0 {
+			if it.done {
+				return false
+			}
+			it.err = it.fetch()
+			continue
+		}
+
+		build := &it.page[0]
+		it.page = it.page[1:]
+		started := build.StartTime()
+		if !it.opts.Until.IsZero() && started.After(it.opts.Until) {
+			continue
+		}
+		if !it.opts.Since.IsZero() && started.Before(it.opts.Since) {
+			// History is ordered by build number, so the rest of builds are even older
+			it.page, it.done = nil, true
+			return false
+		}
+		if it.matches(build) {
+			it.build = build
+			return true
+		}
+	}
+	return false
+}
+
+// Build returns the current build
+func (it *BuildIterator) Build() *Build {
+	return it.build
+}
+
+// Err returns error occurred during iteration, if any
+func (it *BuildIterator) Err() error {
+	return it.err
+}
+
+func (it *BuildIterator) matches(build *Build) bool {
+	for _, filter := range it.opts.Filters {
+		if !filter(build) {
+			return false
+		}
+	}
+	return true
+}
+
+// fetch requests the next page of allBuilds; {m,n} range includes m-th build and excludes n-th one
+func (it *BuildIterator) fetch() error {
+	var receiver struct {
+		AllBuilds []Build `json:"allBuilds"`
+	}
+	params := map[string]string{
+		"tree": fmt.Sprintf("allBuilds[%s]{%d,%d}", it.opts.Fields, it.offset, it.offset+it.opts.PageSize),
+	}
+	apiRequest := &request.JenkinsAPIRequest{
+		Method:      "GET",
+		Route:       it.name.Route(),
+		Format:      request.JenkinsAPIFormatJSON,
+		QueryParams: params,
+		DumpMethod:  request.ResponseDumpDefaultJSON,
+	}
+	if err := it.client.processor.GetJSON(it.ctx, apiRequest, &receiver); err != nil {
+		return err
+	}
+	it.page = receiver.AllBuilds
+	it.offset += len(receiver.AllBuilds)
+	it.done = len(receiver.AllBuilds) < it.opts.PageSize
+	return nil
+}
@@ -4,8 +4,16 @@ package jenkins_test
 
 import (
 	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,6 +45,110 @@ const (
   </builders>
   <publishers/>
   <buildWrappers/>
+</project>
+	`
+	pipelineWithStages string = `
+pipeline {
+  agent any
+  stages {
+    stage('Build') {
+      steps { echo 'building' }
+    }
+    stage('Test') {
+      steps {
+        echo 'testing'
+        error 'tests failed'
+      }
+    }
+  }
+}
+	`
+	pipelineWithInput string = `
+pipeline {
+  agent none
+  stages {
+    stage('Deploy') {
+      input {
+        message 'Deploy?'
+        parameters { string(name: 'TARGET', defaultValue: 'staging') }
+      }
+      steps { echo "deploying to ${TARGET}" }
+    }
+  }
+}
+	`
+	pipelineWithTests string = `
+pipeline {
+  agent any
+  stages {
+    stage('Test') {
+      steps {
+        writeFile file: 'report.xml', text: '''
+<testsuite name="pkg.Test" tests="2">
+  <testcase classname="pkg.Test" name="passing" time="0.1"><system-out>hello</system-out></testcase>
+  <testcase classname="pkg.Test" name="failing" time="0.2"><failure message="boom">stack trace</failure></testcase>
+</testsuite>
+'''
+        junit 'report.xml'
+      }
+    }
+  }
+}
+	`
+	pipelineInvalid string = `pipeline {
+  agent any
+  stages {
+    stage('Build') { }
+  }
+}
+	`
+	jobConfigWithParams string = `
+<project>
+  <actions/>
+  <description></description>
+  <keepDependencies>false</keepDependencies>
+  <properties>
+    <hudson.model.ParametersDefinitionProperty>
+      <parameterDefinitions>
+        <hudson.model.StringParameterDefinition>
+          <name>GREETING</name>
+          <defaultValue>hello</defaultValue>
+        </hudson.model.StringParameterDefinition>
+        <hudson.model.BooleanParameterDefinition>
+          <name>LOUD</name>
+          <defaultValue>false</defaultValue>
+        </hudson.model.BooleanParameterDefinition>
+        <hudson.model.ChoiceParameterDefinition>
+          <name>TARGET</name>
+          <choices class="java.util.Arrays$ArrayList">
+            <a class="string-array">
+              <string>world</string>
+              <string>jenkins</string>
+            </a>
+          </choices>
+        </hudson.model.ChoiceParameterDefinition>
+        <hudson.model.RunParameterDefinition>
+          <name>UPSTREAM</name>
+          <projectName>test2</projectName>
+          <filter>ALL</filter>
+        </hudson.model.RunParameterDefinition>
+      </parameterDefinitions>
+    </hudson.model.ParametersDefinitionProperty>
+  </properties>
+  <scm class="hudson.scm.NullSCM"/>
+  <canRoam>true</canRoam>
+  <disabled>false</disabled>
+  <blockBuildWhenDownstreamBuilding>false</blockBuildWhenDownstreamBuilding>
+  <blockBuildWhenUpstreamBuilding>false</blockBuildWhenUpstreamBuilding>
+  <triggers/>
+  <concurrentBuild>false</concurrentBuild>
+  <builders>
+    <hudson.tasks.Shell>
+      <command>echo "$GREETING $TARGET ($LOUD)";</command>
+    </hudson.tasks.Shell>
+  </builders>
+  <publishers/>
+  <buildWrappers/>
 </project>
 	`
 )
@@ -68,18 +180,33 @@ func (s *jenkinsSuite) TestRootInfo() {
 	s.Assert().NotZero(info.NumExecutors)
 }
 
+// Test that cancelled context interrupts request
+func (s *jenkinsSuite) TestContextCancelled() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	info, err := s.client.RootInfo(ctx)
+	s.Assert().Equal(context.Canceled, err)
+	s.Assert().Nil(info)
+
+	ctx, cancel = context.WithTimeout(s.ctx, time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	_, err = s.client.JobExists(ctx, "test1")
+	s.Assert().Equal(context.DeadlineExceeded, err)
+}
+
 // Test create, get, build, delete simple (non parametrized) job
 func (s *jenkinsSuite) TestSimpleJobActions() {
 	var (
 		err  error
-		name string = "test1"
+		name jenkins.JobPath = "test1"
 	)
 
 	// Create job
 	jobCreated, err := s.client.JobCreate(s.ctx, name, jobConfigWithSleep)
 	s.Assert().NotNil(jobCreated)
 	s.Assert().NoError(err)
-	s.Assert().Equal(name, jobCreated.DisplayName)
+	s.Assert().Equal(string(name), jobCreated.DisplayName)
 
 	// Check that Job exists, but is not enqueued or building
 	exists, err := s.client.JobExists(s.ctx, name)
@@ -100,34 +227,36 @@ func (s *jenkinsSuite) TestSimpleJobActions() {
 	s.Assert().NotNil(invoked.URL)
 	s.Assert().NotZero(invoked.ID)
 
-	// Poll API until build will pass the queue (1) and building process (2)
-	for {
-		enqueued, err := s.client.JobInQueue(s.ctx, name)
-		s.Assert().NoError(err)
-		if !enqueued {
-			s.T().Log("Job has passed the queue")
-			break
-		}
-		s.T().Log("Job is in queue. Waiting for 1 sec...")
-		time.Sleep(1 * time.Second)
-	}
+	// Wait until build will pass the queue (1) and building process (2)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, &jenkins.WaitOptions{
+		Interval:    500 * time.Millisecond,
+		Backoff:     1.5,
+		MaxInterval: 2 * time.Second,
+		OnQueued:    func(*jenkins.QueueItem) { s.T().Log("Job is in queue") },
+		OnBuilding:  func(*jenkins.Build) { s.T().Log("Job is building") },
+	})
+	s.Require().NoError(err)
+	s.Assert().False(build.Building)
 
-	for {
-		building, err = s.client.JobIsBuilding(s.ctx, name)
+	// Read console output of the finished build in both ways
+	consoleText, err := s.client.BuildConsoleText(s.ctx, name, build.Number)
+	s.Assert().NoError(err)
+	s.Assert().Contains(consoleText, "sleep 3")
+	s.Assert().Contains(consoleText, "Finished: SUCCESS")
+
+	stream, err := s.client.BuildConsoleStream(s.ctx, name, build.Number)
+	if s.Assert().NoError(err) {
+		streamed, err := ioutil.ReadAll(stream)
 		s.Assert().NoError(err)
-		if !building {
-			fmt.Println("Job has been built")
-			break
-		}
-		fmt.Println("Job is building. Waiting for 1 sec...")
-		time.Sleep(1 * time.Second)
+		s.Assert().Equal(consoleText, string(streamed))
+		s.Assert().NoError(stream.Close())
 	}
 
 	// Get and check job information
 	jobObtained, err := s.client.JobGet(s.ctx, name, 0)
 	s.Assert().NoError(err)
 	s.Assert().NotNil(jobObtained)
-	s.Assert().Equal(name, jobObtained.DisplayName)
+	s.Assert().Equal(string(name), jobObtained.DisplayName)
 
 	// Check some build-related job information
 	s.Assert().False(jobObtained.InQueue)
@@ -158,6 +287,860 @@ func (s *jenkinsSuite) TestSimpleJobActions() {
 	// Delete job
 	err = s.client.JobDelete(s.ctx, name)
 	s.Assert().Nil(err)
+
+	// Deleted job must be reported as missing
+	_, err = s.client.JobGet(s.ctx, name, 0)
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
+	var apiErr *jenkins.APIError
+	if s.Assert().True(errors.As(err, &apiErr)) {
+		s.Assert().Equal(http.StatusNotFound, apiErr.StatusCode)
+	}
+	err = s.client.JobDelete(s.ctx, name)
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
+}
+
+// Test build invocation with string, boolean and choice parameters
+func (s *jenkinsSuite) TestParametrizedJobActions() {
+	var name jenkins.JobPath = "test2"
+
+	_, err := s.client.JobCreate(s.ctx, name, jobConfigWithParams)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	params := []jenkins.BuildParameter{
+		jenkins.NewStringParameter("GREETING", "hi"),
+		jenkins.NewBooleanParameter("LOUD", true),
+		jenkins.NewChoiceParameter("TARGET", "jenkins"),
+	}
+	// UPSTREAM run parameter has no default value while the job has no builds,
+	// yet Jenkins builds the job without it
+	s.Assert().NoError(s.client.BuildParamsValidate(s.ctx, name, params))
+
+	// Check client-side validation of malformed parameters
+	err = s.client.BuildParamsValidate(s.ctx, name, []jenkins.BuildParameter{
+		jenkins.NewStringParameter("UNKNOWN", "value"),
+		jenkins.NewStringParameter("LOUD", "very"),
+		jenkins.NewChoiceParameter("TARGET", "nobody"),
+	})
+	var validationErr *jenkins.ParameterValidationError
+	if s.Assert().True(errors.As(err, &validationErr)) && s.Assert().Len(validationErr.Problems, 3) {
+		s.Assert().Equal(jenkins.ParameterUnknown, validationErr.Problems[0].Reason)
+		s.Assert().Equal(jenkins.ParameterWrongType, validationErr.Problems[1].Reason)
+		s.Assert().Equal(jenkins.ParameterNotAllowed, validationErr.Problems[2].Reason)
+	}
+
+	// Boolean values may be given as strings
+	s.Assert().NoError(s.client.BuildParamsValidate(s.ctx, name, []jenkins.BuildParameter{
+		jenkins.NewStringParameter("LOUD", "true"),
+	}))
+
+	invoked, err := s.client.BuildInvokeWithParams(s.ctx, name, params)
+	s.Require().NoError(err)
+	s.Assert().NotZero(invoked.ID)
+
+	// Wait until the build will be started and finished
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("SUCCESS", build.Result)
+
+	// Check parameters read back from build actions
+	obtained := make(map[string]jenkins.BuildParameter)
+	for _, action := range build.Actions {
+		for _, param := range action.Parameters {
+			obtained[param.Name] = param
+		}
+	}
+	s.Assert().Equal(jenkins.BuildParameterString, obtained["GREETING"].Kind)
+	s.Assert().Equal("hi", obtained["GREETING"].StringValue())
+	s.Assert().Equal(jenkins.BuildParameterBoolean, obtained["LOUD"].Kind)
+	s.Assert().True(obtained["LOUD"].BoolValue())
+	s.Assert().Equal("jenkins", obtained["TARGET"].StringValue())
+
+	// Run parameter referencing the previous build is read back in "job#number" form
+	upstream := fmt.Sprintf("%s#%d", name, build.Number)
+	invoked, err = s.client.BuildInvokeWithParams(s.ctx, name, []jenkins.BuildParameter{
+		jenkins.NewRunParameter("UPSTREAM", upstream),
+	})
+	s.Require().NoError(err)
+	build, err = s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	for _, action := range build.Actions {
+		for _, param := range action.Parameters {
+			obtained[param.Name] = param
+		}
+	}
+	s.Assert().Equal(jenkins.BuildParameterRun, obtained["UPSTREAM"].Kind)
+	s.Assert().Equal(upstream, obtained["UPSTREAM"].StringValue())
+}
+
+// Test listing and cancellation of queue items
+func (s *jenkinsSuite) TestQueueActions() {
+	var name jenkins.JobPath = "test3"
+
+	// Job bound to a missing node stays in queue forever
+	config := strings.Replace(jobConfigWithSleep, "<canRoam>true</canRoam>",
+		"<assignedNode>missing-node</assignedNode><canRoam>false</canRoam>", 1)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+
+	queue, err := s.client.QueueList(s.ctx)
+	s.Require().NoError(err)
+	items := queue.Filter(jenkins.QueueFilterByJob(name))
+	if s.Assert().Len(items, 1) {
+		s.Assert().Equal(invoked.ID, items[0].ID)
+		s.Assert().Equal(string(name), items[0].Task.Name)
+		s.Assert().NotEmpty(items[0].Why)
+		s.Assert().Nil(items[0].Executable)
+	}
+	s.Assert().Empty(queue.Filter(jenkins.QueueFilterByJob(name), jenkins.QueueFilterOlderThan(time.Hour)))
+
+	// Cancel item and make sure that waiting for it fails
+	s.Require().NoError(s.client.QueueItemCancel(s.ctx, invoked.ID))
+	item, err := s.client.QueueItemGet(s.ctx, invoked.ID)
+	s.Require().NoError(err)
+	s.Assert().True(item.Cancelled)
+
+	_, err = s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Assert().True(errors.Is(err, jenkins.ErrQueueItemCancelled))
+}
+
+// Test abortion of a running build
+func (s *jenkinsSuite) TestBuildAbort() {
+	var name jenkins.JobPath = "test4"
+
+	config := strings.Replace(jobConfigWithSleep, "sleep 3;", "sleep 300;", 1)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+
+	// Wait until the build leaves the queue
+	var item *jenkins.QueueItem
+	for item == nil || item.Executable == nil {
+		time.Sleep(1 * time.Second)
+		item, err = s.client.QueueItemGet(s.ctx, invoked.ID)
+		s.Require().NoError(err)
+	}
+
+	step, err := s.client.BuildAbort(s.ctx, name, item.Executable.Number, nil)
+	s.Assert().NoError(err)
+	s.Assert().Equal(jenkins.BuildAbortStop, step)
+
+	build, err := s.client.BuildGetByNumber(s.ctx, name, item.Executable.Number)
+	s.Require().NoError(err)
+	s.Assert().False(build.Building)
+	s.Assert().Equal("ABORTED", build.Result)
+}
+
+// Test downloading of archived artifacts
+func (s *jenkinsSuite) TestArtifacts() {
+	var name jenkins.JobPath = "test5"
+
+	config := strings.NewReplacer(
+		// out/sub/a.txt has the same file name as out/a.txt but different content
+		"sleep 3;", "mkdir -p out/sub; echo first > out/a.txt; echo second > 'out/sub/b c.txt'; echo third > out/sub/a.txt;",
+		"<publishers/>", `<publishers>
+    <hudson.tasks.ArtifactArchiver>
+      <artifacts>out/**</artifacts>
+      <fingerprint>true</fingerprint>
+    </hudson.tasks.ArtifactArchiver>
+  </publishers>`,
+	).Replace(jobConfigWithSleep)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Require().Len(build.Artifacts, 3)
+
+	// Download single artifact
+	content, err := s.client.ArtifactDownload(s.ctx, name, build.Number, "out/sub/b c.txt")
+	if s.Assert().NoError(err) {
+		data, err := ioutil.ReadAll(content)
+		s.Assert().NoError(err)
+		s.Assert().Equal("second\n", string(data))
+		s.Assert().NoError(content.Close())
+	}
+
+	// Download all artifacts in both ways
+	for _, zip := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "artifacts")
+		s.Require().NoError(err)
+		defer os.RemoveAll(dir)
+
+		opts := &jenkins.ArtifactsDownloadOptions{Zip: zip, VerifyChecksum: true}
+		paths, err := s.client.ArtifactsDownloadAll(s.ctx, name, build.Number, dir, opts)
+		s.Assert().NoError(err)
+		s.Assert().Len(paths, 3)
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "out", "a.txt"))
+		s.Assert().NoError(err)
+		s.Assert().Equal("first\n", string(data))
+		data, err = ioutil.ReadFile(filepath.Join(dir, "out", "sub", "a.txt"))
+		s.Assert().NoError(err)
+		s.Assert().Equal("third\n", string(data))
+	}
+}
+
+// Test reading and updating job configuration
+func (s *jenkinsSuite) TestJobConfig() {
+	var name jenkins.JobPath = "test6"
+
+	_, err := s.client.JobCreate(s.ctx, name, jobConfigWithSleep)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	original, err := s.client.JobConfigGet(s.ctx, name)
+	s.Require().NoError(err)
+	s.Assert().Contains(original, "sleep 3;")
+
+	// Update configuration that hasn't been changed since it was obtained
+	updated := strings.Replace(original, "sleep 3;", "sleep 1;", 1)
+	s.Assert().NoError(s.client.JobConfigUpdateIfUnchanged(s.ctx, name, original, updated))
+
+	// The second update based on outdated configuration must be rejected
+	conflicting := strings.Replace(original, "sleep 3;", "sleep 2;", 1)
+	err = s.client.JobConfigUpdateIfUnchanged(s.ctx, name, original, conflicting)
+	s.Assert().True(errors.Is(err, jenkins.ErrJobConfigChanged))
+
+	obtained, err := s.client.JobConfigGet(s.ctx, name)
+	s.Assert().NoError(err)
+	s.Assert().Contains(obtained, "sleep 1;")
+}
+
+// Test typed job configuration round trip
+func (s *jenkinsSuite) TestTypedJobConfig() {
+	var name jenkins.JobPath = "test7"
+
+	config := jenkins.NewFreestyleJobConfig()
+	config.Description = "typed"
+	s.Require().NoError(config.Builders.Add(&jenkins.ShellBuilder{Command: "echo typed"}))
+	s.Require().NoError(config.Triggers.Add(&jenkins.TimerTrigger{Spec: "H 3 * * *"}))
+	parameters := &jenkins.ParametersDefinitionProperty{}
+	s.Require().NoError(parameters.ParameterDefinitions.Add(
+		jenkins.NewChoiceParameterDefinitionConfig("TARGET", "", "world", "jenkins"),
+	))
+	s.Require().NoError(config.Properties.Add(parameters))
+	raw, err := config.Marshal()
+	s.Require().NoError(err)
+
+	_, err = s.client.JobCreate(s.ctx, name, raw)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	// Read configuration back and check that typed sections survived
+	raw, err = s.client.JobConfigGet(s.ctx, name)
+	s.Require().NoError(err)
+	obtained, err := jenkins.ParseFreestyleJobConfig(raw)
+	s.Require().NoError(err)
+	s.Assert().Equal("typed", obtained.Description)
+
+	var shell jenkins.ShellBuilder
+	element, found := obtained.Builders.Find("hudson.tasks.Shell")
+	if s.Assert().True(found) {
+		s.Assert().NoError(element.Decode(&shell))
+		s.Assert().Equal("echo typed", shell.Command)
+	}
+
+	// Elements unknown to the library must be preserved verbatim
+	obtained.Publishers.Items = append(obtained.Publishers.Items, jenkins.XMLElement{
+		XMLName: xml.Name{Local: "hudson.tasks.Fingerprinter"},
+		Inner:   "<targets>out/**</targets>",
+	})
+	raw, err = obtained.Marshal()
+	s.Require().NoError(err)
+	s.Require().NoError(s.client.JobConfigUpdate(s.ctx, name, raw))
+
+	raw, err = s.client.JobConfigGet(s.ctx, name)
+	s.Require().NoError(err)
+	s.Assert().Contains(raw, "<targets>out/**</targets>")
+	s.Assert().Contains(raw, "<spec>H 3 * * *</spec>")
+}
+
+// Test jobs nested into folders
+func (s *jenkinsSuite) TestFolders() {
+	var (
+		folder    = jenkins.NewJobPath("test folder")
+		subfolder = jenkins.NewJobPath("test folder", "sub folder")
+		name      = jenkins.NewJobPath("test folder", "sub folder", "nested job")
+	)
+
+	_, err := s.client.FolderCreate(s.ctx, folder)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, folder)
+	_, err = s.client.FolderCreate(s.ctx, subfolder)
+	s.Require().NoError(err)
+
+	job, err := s.client.JobCreate(s.ctx, name, jobConfigWithSleep)
+	s.Require().NoError(err)
+	s.Assert().Equal(string(name), job.FullName)
+
+	exists, err := s.client.JobExists(s.ctx, name)
+	s.Assert().NoError(err)
+	s.Assert().True(exists)
+
+	// Build nested job
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("SUCCESS", build.Result)
+	buildByQueueID, err := s.client.BuildGetByQueueID(s.ctx, name, invoked.ID)
+	s.Assert().NoError(err)
+	s.Assert().Equal(build.Number, buildByQueueID.Number)
+
+	// List folder contents
+	items, err := s.client.FolderList(s.ctx, folder)
+	s.Assert().NoError(err)
+	if s.Assert().Len(items, 1) {
+		s.Assert().Equal(subfolder, items[0].Path())
+	}
+	items, err = s.client.JobListRecursive(s.ctx, folder)
+	s.Assert().NoError(err)
+	if s.Assert().Len(items, 2) {
+		s.Assert().Equal(name, items[1].Path())
+	}
+}
+
+// Test job lifecycle operations
+func (s *jenkinsSuite) TestJobLifecycle() {
+	var (
+		name    jenkins.JobPath = "test8"
+		copied  jenkins.JobPath = "test8-copy"
+		renamed jenkins.JobPath = "test8-renamed"
+	)
+
+	_, err := s.client.JobCreate(s.ctx, name, jobConfigWithSleep)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	job, err := s.client.JobCopy(s.ctx, name, copied)
+	s.Require().NoError(err)
+	s.Assert().Equal(string(copied), job.Name)
+
+	job, err = s.client.JobRename(s.ctx, copied, string(renamed))
+	s.Require().NoError(err)
+	s.Assert().Equal(string(renamed), job.Name)
+	defer s.client.JobDelete(s.ctx, renamed)
+
+	exists, err := s.client.JobExists(s.ctx, copied)
+	s.Assert().NoError(err)
+	s.Assert().False(exists)
+
+	job, err = s.client.JobDisable(s.ctx, renamed)
+	s.Require().NoError(err)
+	s.Assert().False(job.Buildable)
+
+	job, err = s.client.JobEnable(s.ctx, renamed)
+	s.Require().NoError(err)
+	s.Assert().True(job.Buildable)
+
+	job, err = s.client.JobSetDescription(s.ctx, renamed, "renamed copy")
+	s.Require().NoError(err)
+	s.Assert().Equal("renamed copy", job.Description)
+}
+
+// Test multibranch pipeline without branch sources
+func (s *jenkinsSuite) TestMultiBranch() {
+	var name jenkins.JobPath = "test9"
+
+	config := `<org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject/>`
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	project, err := s.client.MultiBranchGet(s.ctx, name)
+	s.Require().NoError(err)
+	s.Assert().Equal(jenkins.MultiBranchProjectClass, project.Class)
+	s.Assert().False(project.IsOrganizationFolder())
+
+	s.Require().NoError(s.client.MultiBranchScan(s.ctx, name))
+	time.Sleep(3 * time.Second)
+	log, err := s.client.MultiBranchScanLog(s.ctx, name)
+	s.Assert().NoError(err)
+	s.Assert().NotEmpty(log)
+
+	branches, err := s.client.MultiBranchBranches(s.ctx, name)
+	s.Assert().NoError(err)
+	s.Assert().Empty(branches)
+
+	s.Assert().Equal(jenkins.JobPath("test9/feature%2Fx"), jenkins.BranchJobPath(name, "feature/x"))
+}
+
+// Test stage view of a pipeline build
+func (s *jenkinsSuite) TestPipelineStages() {
+	var name jenkins.JobPath = "test10"
+
+	config, err := jenkins.NewPipelineJobConfig(pipelineWithStages).Marshal()
+	s.Require().NoError(err)
+	_, err = s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("FAILURE", build.Result)
+
+	run, err := s.client.PipelineRunDescribe(s.ctx, name, build.Number)
+	s.Require().NoError(err)
+	s.Assert().Equal(jenkins.PipelineStatusFailed, run.Status)
+	if !s.Assert().Len(run.Stages, 2) {
+		return
+	}
+	s.Assert().Equal(jenkins.PipelineStatusSuccess, run.Stages[0].Status)
+
+	failed := run.FailedStage()
+	s.Require().NotNil(failed)
+	s.Assert().Equal("Test", failed.Name)
+	s.Assert().NotNil(failed.Error)
+
+	// Drill into the failed stage and its steps
+	stage, err := s.client.PipelineNodeDescribe(s.ctx, name, build.Number, failed.ID)
+	s.Require().NoError(err)
+	if s.Assert().NotEmpty(stage.StageFlowNodes) {
+		log, err := s.client.PipelineNodeLog(s.ctx, name, build.Number, stage.StageFlowNodes[0].ID)
+		s.Assert().NoError(err)
+		s.Assert().Contains(log.Text, "testing")
+	}
+}
+
+// Test approval of pipeline input steps
+func (s *jenkinsSuite) TestPipelineInput() {
+	var name jenkins.JobPath = "test11"
+
+	config, err := jenkins.NewPipelineJobConfig(pipelineWithInput).Marshal()
+	s.Require().NoError(err)
+	_, err = s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+
+	// Wait until the build is paused by input step
+	var (
+		item   *jenkins.QueueItem
+		inputs []jenkins.PipelineInput
+	)
+	for item == nil || item.Executable == nil {
+		time.Sleep(1 * time.Second)
+		item, err = s.client.QueueItemGet(s.ctx, invoked.ID)
+		s.Require().NoError(err)
+	}
+	number := item.Executable.Number
+	for len(inputs) == 0 {
+		time.Sleep(1 * time.Second)
+		inputs, err = s.client.PipelinePendingInputs(s.ctx, name, number)
+		s.Require().NoError(err)
+	}
+	s.Assert().Equal("Deploy?", inputs[0].Message)
+	if s.Assert().Len(inputs[0].Inputs, 1) {
+		s.Assert().Equal(jenkins.BuildParameterString, inputs[0].Inputs[0].Kind())
+	}
+
+	params := []jenkins.BuildParameter{jenkins.NewStringParameter("TARGET", "production")}
+	s.Require().NoError(s.client.PipelineInputSubmit(s.ctx, name, number, inputs[0].ID, params))
+
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("SUCCESS", build.Result)
+	console, err := s.client.BuildConsoleText(s.ctx, name, number)
+	s.Assert().NoError(err)
+	s.Assert().Contains(console, "deploying to production")
+}
+
+// Test linting of declarative Jenkinsfiles
+func (s *jenkinsSuite) TestPipelineValidate() {
+	diagnostics, err := s.client.PipelineValidate(s.ctx, pipelineWithStages)
+	s.Require().NoError(err)
+	s.Assert().Empty(diagnostics)
+
+	diagnostics, err = s.client.PipelineValidate(s.ctx, pipelineInvalid)
+	s.Require().NoError(err)
+	if s.Assert().NotEmpty(diagnostics) {
+		s.Assert().Equal(4, diagnostics[0].Line)
+		s.Assert().NotZero(diagnostics[0].Column)
+		s.Assert().NotEmpty(diagnostics[0].Message)
+	}
+
+	diagnostics, err = s.client.PipelineValidate(s.ctx, "node { echo 'scripted' }")
+	s.Require().NoError(err)
+	s.Assert().NotEmpty(diagnostics)
+}
+
+// Test replay and restart of pipeline builds
+func (s *jenkinsSuite) TestPipelineRerun() {
+	var name jenkins.JobPath = "test12"
+
+	config, err := jenkins.NewPipelineJobConfig(pipelineWithStages).Marshal()
+	s.Require().NoError(err)
+	_, err = s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	original, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("FAILURE", original.Result)
+
+	// Replay with the failing stage removed
+	script := strings.Replace(pipelineWithStages, "error 'tests failed'", "echo 'tests passed'", 1)
+	invoked, err = s.client.BuildReplay(s.ctx, name, original.Number, script, nil)
+	s.Require().NoError(err)
+	replayed, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("SUCCESS", replayed.Result)
+	console, err := s.client.BuildConsoleText(s.ctx, name, replayed.Number)
+	s.Assert().NoError(err)
+	s.Assert().Contains(console, "tests passed")
+
+	// Restart the original build from the failed stage
+	invoked, err = s.client.BuildRestartFromStage(s.ctx, name, original.Number, "Test")
+	s.Require().NoError(err)
+	restarted, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().True(restarted.Number > replayed.Number)
+	run, err := s.client.PipelineRunDescribe(s.ctx, name, restarted.Number)
+	s.Require().NoError(err)
+	if failed := run.FailedStage(); s.Assert().NotNil(failed) {
+		s.Assert().Equal("Test", failed.Name)
+	}
+
+	// Unknown builds can't be re-run
+	_, err = s.client.BuildReplay(s.ctx, name, 100, script, nil)
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
+}
+
+// Test retrieval of JUnit test reports
+func (s *jenkinsSuite) TestBuildTestReport() {
+	var name jenkins.JobPath = "test13"
+
+	config, err := jenkins.NewPipelineJobConfig(pipelineWithTests).Marshal()
+	s.Require().NoError(err)
+	_, err = s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	s.Assert().Equal("UNSTABLE", build.Result)
+
+	report, err := s.client.BuildTestReport(s.ctx, name, build.Number)
+	s.Require().NoError(err)
+	s.Assert().Equal(2, report.TotalCount())
+	s.Assert().Equal(1, report.PassCount)
+	s.Assert().Equal(1, report.FailCount)
+	if s.Assert().Len(report.Suites, 1) && s.Assert().Len(report.Suites[0].Cases, 2) {
+		passed := report.Suites[0].Cases[0]
+		s.Assert().Equal(jenkins.TestStatusPassed, passed.Status)
+		s.Assert().Equal(100*time.Millisecond, passed.Duration())
+		s.Assert().Contains(passed.Stdout, "hello")
+	}
+	if failed := report.FailedCases(); s.Assert().Len(failed, 1) {
+		s.Assert().Equal("failing", failed[0].Name)
+		s.Assert().Equal("boom", failed[0].ErrorDetails)
+	}
+
+	// Builds of jobs without JUnit publisher have no report
+	var plain jenkins.JobPath = "test19"
+	_, err = s.client.JobCreate(s.ctx, plain, strings.Replace(jobConfigWithSleep, "sleep 3;", "", 1))
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, plain)
+
+	invoked, err = s.client.BuildInvoke(s.ctx, plain)
+	s.Require().NoError(err)
+	build, err = s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+	_, err = s.client.BuildTestReport(s.ctx, plain, build.Number)
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
+}
+
+// Test job information requested with depth
+func (s *jenkinsSuite) TestJobGetDepth() {
+	var name jenkins.JobPath = "test17"
+
+	config := strings.Replace(jobConfigWithSleep, "sleep 3;", "", 1)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+
+	// Depth 1 expands builds with their details
+	job, err := s.client.JobGet(s.ctx, name, 1)
+	s.Require().NoError(err)
+	s.Assert().Equal(build.Number, job.LastBuild.Number)
+	s.Assert().Equal("SUCCESS", job.LastBuild.Result)
+
+	_, err = s.client.JobGet(s.ctx, name, 3)
+	s.Assert().Error(err)
+}
+
+// Test paginated iteration over build history
+func (s *jenkinsSuite) TestBuildHistory() {
+	var name jenkins.JobPath = "test14"
+
+	config := strings.Replace(jobConfigWithSleep, "sleep 3;", "", 1)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	started := time.Now().Add(-time.Minute)
+	for i := 0; i < 3; i++ {
+		invoked, err := s.client.BuildInvoke(s.ctx, name)
+		s.Require().NoError(err)
+		_, err = s.client.WaitForBuild(s.ctx, invoked, nil)
+		s.Require().NoError(err)
+	}
+
+	// Builds are walked from the newest to the oldest one across several pages
+	var numbers []int
+	history := s.client.BuildHistory(s.ctx, name, &jenkins.BuildHistoryOptions{PageSize: 2})
+	for history.Next() {
+		numbers = append(numbers, history.Build().Number)
+	}
+	s.Require().NoError(history.Err())
+	s.Assert().Equal([]int{3, 2, 1}, numbers)
+
+	// Filters
+	count := func(opts *jenkins.BuildHistoryOptions) int {
+		var result int
+		history := s.client.BuildHistory(s.ctx, name, opts)
+		for history.Next() {
+			result++
+		}
+		s.Assert().NoError(history.Err())
+		return result
+	}
+	s.Assert().Equal(3, count(&jenkins.BuildHistoryOptions{
+		Since:   started,
+		Filters: []jenkins.BuildFilter{jenkins.BuildFilterByResult("SUCCESS")},
+	}))
+	s.Assert().Equal(0, count(&jenkins.BuildHistoryOptions{Since: time.Now().Add(time.Hour)}))
+	s.Assert().Equal(0, count(&jenkins.BuildHistoryOptions{Until: started}))
+	s.Assert().Equal(0, count(&jenkins.BuildHistoryOptions{
+		Filters: []jenkins.BuildFilter{jenkins.BuildFilterByResult("FAILURE")},
+	}))
+	s.Assert().Equal(3, count(&jenkins.BuildHistoryOptions{
+		Fields:  "number,actions[causes[_class]]",
+		Filters: []jenkins.BuildFilter{jenkins.BuildFilterByCause("hudson.model.Cause$UserIdCause")},
+	}))
+	// Timestamp is requested for Since and Until even if it is not selected
+	s.Assert().Equal(3, count(&jenkins.BuildHistoryOptions{Fields: "number", Since: started}))
+}
+
+// Test partial responses selected by tree expressions
+func (s *jenkinsSuite) TestTreeSelection() {
+	var name jenkins.JobPath = "test15"
+
+	s.Assert().Equal("name,builds[number,url]{0,10}",
+		jenkins.NewTree("name").NestedRange("builds", jenkins.NewTree("number", "url"), 0, 10).String())
+
+	// Trees are not changed when extended
+	base := jenkins.NewTree("number")
+	s.Assert().Equal("number,url", base.Fields("url").String())
+	s.Assert().Equal("number", base.String())
+	// Nested selection replaces plain field
+	s.Assert().Equal("actions[causes]",
+		jenkins.NewTree("actions").Nested("actions", jenkins.NewTree("causes")).String())
+
+	config := strings.Replace(jobConfigWithSleep, "sleep 3;", "", 1)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	invoked, err := s.client.BuildInvoke(s.ctx, name)
+	s.Require().NoError(err)
+	build, err := s.client.WaitForBuild(s.ctx, invoked, nil)
+	s.Require().NoError(err)
+
+	var job struct {
+		Name      string `json:"name"`
+		LastBuild *struct {
+			Number int    `json:"number"`
+			Result string `json:"result"`
+		} `json:"lastBuild"`
+	}
+	s.Assert().Equal("name,lastBuild[number,result]", jenkins.TreeOf(&job).String())
+	buildTree := jenkins.TreeOf(jenkins.Build{}).String()
+	s.Assert().Contains(buildTree, "culprits[absoluteUrl,fullName]")
+	s.Assert().Contains(buildTree, "branch[SHA1,name]")
+	s.Require().NoError(s.client.JobGetInto(s.ctx, name, &job))
+	s.Assert().Equal(string(name), job.Name)
+	if s.Assert().NotNil(job.LastBuild) {
+		s.Assert().Equal(build.Number, job.LastBuild.Number)
+		s.Assert().Equal("SUCCESS", job.LastBuild.Result)
+	}
+
+	var brief struct {
+		jenkins.JobBuildBrief
+		QueueID int `json:"queueId"`
+	}
+	s.Require().NoError(s.client.BuildGetInto(s.ctx, name, build.Number, &brief))
+	s.Assert().Equal(build.Number, brief.Number)
+	s.Assert().Equal(invoked.ID, brief.QueueID)
+
+	// Build decoded from derived tree has the same causes as the whole build
+	causes := func(build *jenkins.Build) []map[string]interface{} {
+		var result []map[string]interface{}
+		for _, action := range build.Actions {
+			result = append(result, action.Causes...)
+		}
+		return result
+	}
+	full, err := s.client.BuildGetByNumber(s.ctx, name, build.Number)
+	s.Require().NoError(err)
+	var selected jenkins.Build
+	s.Require().NoError(s.client.BuildGetInto(s.ctx, name, build.Number, &selected))
+	s.Assert().NotEmpty(causes(full))
+	s.Assert().Equal(causes(full), causes(&selected))
+}
+
+// Test XML API with server-side XPath filtering
+func (s *jenkinsSuite) TestGetXML() {
+	var name jenkins.JobPath = "test16"
+
+	config := strings.Replace(jobConfigWithSleep, "sleep 3;", "", 1)
+	_, err := s.client.JobCreate(s.ctx, name, config)
+	s.Require().NoError(err)
+	defer s.client.JobDelete(s.ctx, name)
+
+	for i := 0; i < 2; i++ {
+		invoked, err := s.client.BuildInvoke(s.ctx, name)
+		s.Require().NoError(err)
+		_, err = s.client.WaitForBuild(s.ctx, invoked, nil)
+		s.Require().NoError(err)
+	}
+
+	// Whole document
+	var job struct {
+		XMLName xml.Name `xml:"freeStyleProject"`
+		Name    string   `xml:"name"`
+		Builds  []int    `xml:"build>number"`
+	}
+	s.Require().NoError(s.client.GetXML(s.ctx, name.Route(), nil, &job))
+	s.Assert().Equal(string(name), job.Name)
+	s.Assert().Len(job.Builds, 2)
+
+	// Text node
+	var text string
+	s.Require().NoError(s.client.GetXML(s.ctx, name.Route(), &jenkins.XMLQuery{XPath: "/freeStyleProject/name/text()"}, &text))
+	s.Assert().Equal(string(name), text)
+
+	// Multiple nodes wrapped into a single root
+	var numbers struct {
+		Numbers []int `xml:"number"`
+	}
+	query := &jenkins.XMLQuery{XPath: "//build/number", Wrapper: "numbers"}
+	s.Require().NoError(s.client.GetXML(s.ctx, name.Route(), query, &numbers))
+	s.Assert().Equal([]int{2, 1}, numbers.Numbers)
+
+	// Excluded nodes
+	query = &jenkins.XMLQuery{Exclude: []string{"//build", "//action"}}
+	s.Require().NoError(s.client.GetXML(s.ctx, name.Route(), query, &text))
+	s.Assert().NotContains(text, "<build")
+	s.Assert().NotContains(text, "<action")
+	s.Assert().Contains(text, "<name>"+string(name)+"</name>")
+
+	// Nothing matched
+	err = s.client.GetXML(s.ctx, name.Route(), &jenkins.XMLQuery{XPath: "/freeStyleProject/missing"}, &text)
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
+}
+
+// Test raw access to arbitrary API routes
+func (s *jenkinsSuite) TestRawAPI() {
+	// JSON API of Jenkins root with tree selection
+	var root map[string]interface{}
+	s.Require().NoError(s.client.GetJSON(s.ctx, "", jenkins.NewTree("numExecutors"), &root))
+	s.Assert().Contains(root, "numExecutors")
+	s.Assert().NotContains(root, "jobs")
+
+	// Plain text route requiring crumb
+	form := url.Values{"script": []string{"println(1 + 1)"}}
+	response, err := s.client.Do(s.ctx, "POST", "/scriptText", nil, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	s.Require().NoError(err)
+	output, err := ioutil.ReadAll(response.Body)
+	s.Assert().NoError(err)
+	s.Assert().NoError(response.Body.Close())
+	s.Assert().Equal("2\n", string(output))
+
+	// XML body creating a job
+	var name jenkins.JobPath = "test18"
+	query := url.Values{"name": []string{string(name)}}
+	response, err = s.client.Do(s.ctx, "POST", "/createItem", query, "application/xml", strings.NewReader(jobConfigWithSleep))
+	s.Require().NoError(err)
+	s.Assert().NoError(response.Body.Close())
+	defer s.client.JobDelete(s.ctx, name)
+	exists, err := s.client.JobExists(s.ctx, name)
+	s.Assert().NoError(err)
+	s.Assert().True(exists)
+
+	// Query parameters and errors
+	response, err = s.client.Do(s.ctx, "GET", "/job/missing/api/json", url.Values{"tree": []string{"name"}}, "", nil)
+	s.Assert().Nil(response)
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
+	var apiError *jenkins.APIError
+	if s.Assert().True(errors.As(err, &apiError)) {
+		s.Assert().Equal(http.StatusNotFound, apiError.StatusCode)
+	}
+}
+
+// Test Groovy script console
+func (s *jenkinsSuite) TestScriptRun() {
+	output, err := s.client.ScriptRun(s.ctx, "import jenkins.model.Jenkins\nprintln \"executors: ${Jenkins.get().numExecutors}\"")
+	s.Require().NoError(err)
+	s.Assert().Contains(output, "executors: ")
+
+	// Default imports of the script console are available without import statements
+	output, err = s.client.ScriptRun(s.ctx, "println Jenkins.instance.numExecutors == Hudson.instance.numExecutors")
+	s.Require().NoError(err)
+	s.Assert().Equal("true\n", output)
+
+	output, err = s.client.ScriptRunOnNode(s.ctx, "(built-in)", "println 'it\\'s me'")
+	s.Require().NoError(err)
+	s.Assert().Equal("it's me\n", output)
+
+	var result struct {
+		Sum   int      `json:"sum"`
+		Names []string `json:"names"`
+	}
+	err = s.client.ScriptRunJSON(s.ctx, "println 'noise'\nreturn [sum: 1 + 2, names: ['a', 'b']]", &result)
+	s.Require().NoError(err)
+	s.Assert().Equal(3, result.Sum)
+	s.Assert().Equal([]string{"a", "b"}, result.Names)
+
+	// Exceptions
+	output, err = s.client.ScriptRun(s.ctx, "println 'before'\nthrow new IllegalStateException('boom')")
+	s.Assert().Empty(output)
+	s.Assert().True(errors.Is(err, jenkins.ErrScriptFailed))
+	var scriptError *jenkins.ScriptError
+	if s.Assert().True(errors.As(err, &scriptError)) {
+		s.Assert().Equal("before", scriptError.Output)
+		s.Assert().Equal("java.lang.IllegalStateException: boom", scriptError.Exception)
+	}
+
+	err = s.client.ScriptRunJSON(s.ctx, "this is not groovy", &result)
+	s.Assert().True(errors.Is(err, jenkins.ErrScriptFailed))
+
+	_, err = s.client.ScriptRunOnNode(s.ctx, "missing-node", "println 1")
+	s.Assert().True(errors.Is(err, jenkins.ErrNotFound))
 }
 
 func (s *jenkinsSuite) TearDownSuite() {}
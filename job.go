@@ -2,33 +2,35 @@ package jenkins
 
 // Job represents the result of job API call
 type Job struct {
-	Actions            []interface{} `json:"actions"`
-	Buildable          bool          `json:"buildable"`
-	Builds             []JobBuildBrief
-	Color              string      `json:"color"`
-	ConcurrentBuild    bool        `json:"concurrentBuild"`
-	Description        string      `json:"description"`
-	DisplayName        string      `json:"displayName"`
-	DisplayNameOrNull  interface{} `json:"displayNameOrNull"`
-	DownstreamProjects []JobBrief  `json:"downstreamProjects"`
-	FirstBuild         JobBuildBrief
+	Actions            []interface{}   `json:"actions"`
+	Buildable          bool            `json:"buildable"`
+	Builds             []JobBuildBrief `json:"builds"`
+	Color              string          `json:"color"`
+	ConcurrentBuild    bool            `json:"concurrentBuild"`
+	Description        string          `json:"description"`
+	DisplayName        string          `json:"displayName"`
+	DisplayNameOrNull  interface{}     `json:"displayNameOrNull"`
+	DownstreamProjects []JobBrief      `json:"downstreamProjects"`
+	FirstBuild         JobBuildBrief   `json:"firstBuild"`
+	FullName           string          `json:"fullName"`
 	HealthReport       []struct {
 		Description   string `json:"description"`
 		IconClassName string `json:"iconClassName"`
 		IconURL       string `json:"iconUrl"`
 		Score         int    `json:"score"`
 	} `json:"healthReport"`
-	InQueue               bool   `json:"inQueue"`
-	KeepDependencies      bool   `json:"keepDependencies"`
-	LastBuild             Build  `json:"lastBuild"`
-	LastCompletedBuild    Build  `json:"lastCompletedBuild"`
-	LastFailedBuild       Build  `json:"lastFailedBuild"`
-	LastStableBuild       Build  `json:"lastStableBuild"`
-	LastSuccessfulBuild   Build  `json:"lastSuccessfulBuild"`
-	LastUnstableBuild     Build  `json:"lastUnstableBuild"`
-	LastUnsuccessfulBuild Build  `json:"lastUnsuccessfulBuild"`
-	Name                  string `json:"name"`
-	NextBuildNumber       int    `json:"nextBuildNumber"`
+	InQueue               bool       `json:"inQueue"`
+	Jobs                  []JobBrief `json:"jobs"`
+	KeepDependencies      bool       `json:"keepDependencies"`
+	LastBuild             Build      `json:"lastBuild"`
+	LastCompletedBuild    Build      `json:"lastCompletedBuild"`
+	LastFailedBuild       Build      `json:"lastFailedBuild"`
+	LastStableBuild       Build      `json:"lastStableBuild"`
+	LastSuccessfulBuild   Build      `json:"lastSuccessfulBuild"`
+	LastUnstableBuild     Build      `json:"lastUnstableBuild"`
+	LastUnsuccessfulBuild Build      `json:"lastUnsuccessfulBuild"`
+	Name                  string     `json:"name"`
+	NextBuildNumber       int        `json:"nextBuildNumber"`
 	Property              []struct {
 		ParameterDefinitions []JobParameterDefinition `json:"parameterDefinitions"`
 	} `json:"property"`
@@ -38,26 +40,68 @@ type Job struct {
 	URL              string      `json:"url"`
 }
 
+// jobActionTree selects fields of Job.Actions; most of job actions export nothing
+// but their classes, except for the definitions of build parameters
+var jobActionTree = NewTree("_class").Nested("parameterDefinitions", TreeOf(JobParameterDefinition{}))
+
+// JenkinsTree selects fields of a job including the ones decoded into interfaces
+func (j *Job) JenkinsTree() *Tree {
+	return fieldsTree(j).
+		Nested("actions", jobActionTree).
+		Nested("queueItem", TreeOf(QueueItem{}))
+}
+
 // JobBrief is a short representation of a common Jenkins item used in various API responses
 type JobBrief struct {
-	Name  string `json:"name"`
-	URL   string `json:"url"`
-	Color string `json:"color"`
+	Class    string `json:"_class"`
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+	URL      string `json:"url"`
+	Color    string `json:"color"`
+}
+
+// Path returns full path of the item
+func (j *JobBrief) Path() JobPath {
+	if j.FullName != "" {
+		return JobPath(j.FullName)
+	}
+	path, err := jobPathFromURL(j.URL)
+	if err != nil || path == "" {
+		return JobPath(j.Name)
+	}
+	return path
 }
 
+// folderConfig is a minimal configuration of a folder provided by Folders plugin
+const folderConfig = `<com.cloudbees.hudson.plugins.folder.Folder/>`
+
 // JobBuildBrief is a short representation of a common Jenkins build used in various API responses
 type JobBuildBrief struct {
-	Number int
-	URL    string
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+// ParameterDefinitions returns definitions of all parameters declared by job
+func (j *Job) ParameterDefinitions() []JobParameterDefinition {
+	var definitions []JobParameterDefinition
+	for _, property := range j.Property {
+		definitions = append(definitions, property.ParameterDefinitions...)
+	}
+	return definitions
 }
 
-// JobParameterDefinition ???
+// JobParameterDefinition describes build parameter declared by job
 type JobParameterDefinition struct {
-	DefaultParameterValue struct {
-		Name  string `json:"name"`
-		Value bool   `json:"value"`
-	} `json:"defaultParameterValue"`
-	Description string `json:"description"`
-	Name        string `json:"name"`
-	Type        string `json:"type"`
+	// DefaultParameterValue is nil for parameters without default value (i. e. files)
+	DefaultParameterValue *BuildParameter `json:"defaultParameterValue"`
+	Description           string          `json:"description"`
+	Name                  string          `json:"name"`
+	Type                  string          `json:"type"`
+	// Choices lists allowed values of choice parameter
+	Choices []string `json:"choices"`
+}
+
+// Kind returns kind of the defined parameter
+func (d *JobParameterDefinition) Kind() BuildParameterKind {
+	return parseBuildParameterKind(d.Type)
 }
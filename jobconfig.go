@@ -0,0 +1,359 @@
+package jenkins
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// XMLElement holds arbitrary XML element verbatim; it is used to preserve
+// configuration of plugins unknown to this library during round trips
+type XMLElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+// NewXMLElement converts typed configuration (i. e. *ShellBuilder) into XMLElement
+func NewXMLElement(v interface{}) (XMLElement, error) {
+	var element XMLElement
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return element, err
+	}
+	err = xml.Unmarshal(data, &element)
+	return element, err
+}
+
+// Decode converts XMLElement into typed configuration (i. e. *ShellBuilder)
+func (e *XMLElement) Decode(v interface{}) error {
+	data, err := xml.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// XMLElements is a list of plugin configurations such as <builders> or <publishers>;
+// order of elements is preserved
+type XMLElements struct {
+	Attrs []xml.Attr   `xml:",any,attr"`
+	Items []XMLElement `xml:",any"`
+}
+
+// Add appends typed configuration (i. e. *ShellBuilder) to the list
+func (e *XMLElements) Add(v interface{}) error {
+	element, err := NewXMLElement(v)
+	if err != nil {
+		return err
+	}
+	e.Items = append(e.Items, element)
+	return nil
+}
+
+// Find returns the first element with a given name (i. e. "hudson.tasks.Shell")
+func (e *XMLElements) Find(name string) (*XMLElement, bool) {
+	for i := range e.Items {
+		if e.Items[i].XMLName.Local == name {
+			return &e.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// FreestyleJobConfig represents config.xml of a freestyle job (<project>)
+type FreestyleJobConfig struct {
+	XMLName                          xml.Name     `xml:"project"`
+	Actions                          XMLElements  `xml:"actions"`
+	Description                      string       `xml:"description"`
+	DisplayName                      string       `xml:"displayName,omitempty"`
+	KeepDependencies                 bool         `xml:"keepDependencies"`
+	Properties                       XMLElements  `xml:"properties"`
+	SCM                              SCMConfig    `xml:"scm"`
+	AssignedNode                     string       `xml:"assignedNode,omitempty"`
+	CanRoam                          bool         `xml:"canRoam"`
+	Disabled                         bool         `xml:"disabled"`
+	BlockBuildWhenDownstreamBuilding bool         `xml:"blockBuildWhenDownstreamBuilding"`
+	BlockBuildWhenUpstreamBuilding   bool         `xml:"blockBuildWhenUpstreamBuilding"`
+	Triggers                         XMLElements  `xml:"triggers"`
+	ConcurrentBuild                  bool         `xml:"concurrentBuild"`
+	Builders                         XMLElements  `xml:"builders"`
+	Publishers                       XMLElements  `xml:"publishers"`
+	BuildWrappers                    XMLElements  `xml:"buildWrappers"`
+	Unknown                          []XMLElement `xml:",any"`
+}
+
+// NewFreestyleJobConfig returns configuration of a job doing nothing,
+// equivalent to the one created by Jenkins UI
+func NewFreestyleJobConfig() *FreestyleJobConfig {
+	return &FreestyleJobConfig{
+		SCM:     SCMConfig{Class: "hudson.scm.NullSCM"},
+		CanRoam: true,
+	}
+}
+
+// ParseFreestyleJobConfig decodes config.xml of a freestyle job
+func ParseFreestyleJobConfig(config string) (*FreestyleJobConfig, error) {
+	var receiver FreestyleJobConfig
+	if err := unmarshalJobConfig(config, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+// Marshal encodes configuration into string accepted by JobCreate and JobConfigUpdate
+func (c *FreestyleJobConfig) Marshal() (string, error) {
+	return marshalJobConfig(c)
+}
+
+// PipelineJobConfig represents config.xml of a pipeline job (<flow-definition>)
+type PipelineJobConfig struct {
+	XMLName          xml.Name           `xml:"flow-definition"`
+	Plugin           string             `xml:"plugin,attr,omitempty"`
+	Actions          XMLElements        `xml:"actions"`
+	Description      string             `xml:"description"`
+	DisplayName      string             `xml:"displayName,omitempty"`
+	KeepDependencies bool               `xml:"keepDependencies"`
+	Properties       XMLElements        `xml:"properties"`
+	Definition       PipelineDefinition `xml:"definition"`
+	// Triggers are stored in PipelineTriggersJobProperty by modern Jenkins versions
+	Triggers *XMLElements `xml:"triggers,omitempty"`
+	Disabled bool         `xml:"disabled"`
+	Unknown  []XMLElement `xml:",any"`
+}
+
+// Pipeline definition classes
+const (
+	// PipelineDefinitionScript is used for pipelines with inline script
+	PipelineDefinitionScript = "org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition"
+	// PipelineDefinitionSCM is used for pipelines loading Jenkinsfile from SCM
+	PipelineDefinitionSCM = "org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition"
+)
+
+// PipelineDefinition describes where pipeline script comes from
+type PipelineDefinition struct {
+	Class  string `xml:"class,attr"`
+	Plugin string `xml:"plugin,attr,omitempty"`
+	// Script and Sandbox are used by PipelineDefinitionScript
+	Script  string `xml:"script,omitempty"`
+	Sandbox bool   `xml:"sandbox,omitempty"`
+	// SCM, ScriptPath and Lightweight are used by PipelineDefinitionSCM
+	SCM         *SCMConfig   `xml:"scm,omitempty"`
+	ScriptPath  string       `xml:"scriptPath,omitempty"`
+	Lightweight bool         `xml:"lightweight,omitempty"`
+	Unknown     []XMLElement `xml:",any"`
+}
+
+// NewPipelineJobConfig returns configuration of a pipeline job running inline script
+func NewPipelineJobConfig(script string) *PipelineJobConfig {
+	return &PipelineJobConfig{
+		Definition: PipelineDefinition{
+			Class:   PipelineDefinitionScript,
+			Script:  script,
+			Sandbox: true,
+		},
+	}
+}
+
+// ParsePipelineJobConfig decodes config.xml of a pipeline job
+func ParsePipelineJobConfig(config string) (*PipelineJobConfig, error) {
+	var receiver PipelineJobConfig
+	if err := unmarshalJobConfig(config, &receiver); err != nil {
+		return nil, err
+	}
+	return &receiver, nil
+}
+
+// Marshal encodes configuration into string accepted by JobCreate and JobConfigUpdate
+func (c *PipelineJobConfig) Marshal() (string, error) {
+	return marshalJobConfig(c)
+}
+
+// SCMConfig describes source code management settings; only Git SCM fields are typed
+type SCMConfig struct {
+	// Class is i. e. "hudson.scm.NullSCM" or "hudson.plugins.git.GitSCM"
+	Class             string            `xml:"class,attr"`
+	Plugin            string            `xml:"plugin,attr,omitempty"`
+	ConfigVersion     string            `xml:"configVersion,omitempty"`
+	UserRemoteConfigs *GitRemoteConfigs `xml:"userRemoteConfigs,omitempty"`
+	Branches          *GitBranchSpecs   `xml:"branches,omitempty"`
+	Unknown           []XMLElement      `xml:",any"`
+}
+
+// NewGitSCMConfig returns configuration of Git SCM for a given repository and branch
+func NewGitSCMConfig(repositoryURL, credentialsID, branch string) *SCMConfig {
+	return &SCMConfig{
+		Class:         "hudson.plugins.git.GitSCM",
+		ConfigVersion: "2",
+		UserRemoteConfigs: &GitRemoteConfigs{
+			Items: []GitRemoteConfig{{URL: repositoryURL, CredentialsID: credentialsID}},
+		},
+		Branches: &GitBranchSpecs{
+			Items: []GitBranchSpec{{Name: branch}},
+		},
+	}
+}
+
+// GitRemoteConfigs is a list of Git repositories
+type GitRemoteConfigs struct {
+	Items []GitRemoteConfig `xml:"hudson.plugins.git.UserRemoteConfig"`
+}
+
+// GitRemoteConfig describes Git repository
+type GitRemoteConfig struct {
+	URL           string `xml:"url"`
+	CredentialsID string `xml:"credentialsId,omitempty"`
+	Name          string `xml:"name,omitempty"`
+	Refspec       string `xml:"refspec,omitempty"`
+}
+
+// GitBranchSpecs is a list of branches to build
+type GitBranchSpecs struct {
+	Items []GitBranchSpec `xml:"hudson.plugins.git.BranchSpec"`
+}
+
+// GitBranchSpec describes branch to build
+type GitBranchSpec struct {
+	Name string `xml:"name"`
+}
+
+// ShellBuilder runs shell script (builders)
+type ShellBuilder struct {
+	XMLName xml.Name `xml:"hudson.tasks.Shell"`
+	Command string   `xml:"command"`
+}
+
+// BatchFileBuilder runs Windows batch script (builders)
+type BatchFileBuilder struct {
+	XMLName xml.Name `xml:"hudson.tasks.BatchFile"`
+	Command string   `xml:"command"`
+}
+
+// ArtifactArchiver archives build artifacts (publishers)
+type ArtifactArchiver struct {
+	XMLName           xml.Name `xml:"hudson.tasks.ArtifactArchiver"`
+	Artifacts         string   `xml:"artifacts"`
+	Excludes          string   `xml:"excludes,omitempty"`
+	AllowEmptyArchive bool     `xml:"allowEmptyArchive"`
+	OnlyIfSuccessful  bool     `xml:"onlyIfSuccessful"`
+	Fingerprint       bool     `xml:"fingerprint"`
+}
+
+// JUnitResultArchiver publishes JUnit test reports (publishers)
+type JUnitResultArchiver struct {
+	XMLName           xml.Name `xml:"hudson.tasks.junit.JUnitResultArchiver"`
+	TestResults       string   `xml:"testResults"`
+	KeepLongStdio     bool     `xml:"keepLongStdio"`
+	AllowEmptyResults bool     `xml:"allowEmptyResults"`
+}
+
+// TimerTrigger starts builds periodically (triggers)
+type TimerTrigger struct {
+	XMLName xml.Name `xml:"hudson.triggers.TimerTrigger"`
+	Spec    string   `xml:"spec"`
+}
+
+// SCMTrigger polls SCM for changes (triggers)
+type SCMTrigger struct {
+	XMLName               xml.Name `xml:"hudson.triggers.SCMTrigger"`
+	Spec                  string   `xml:"spec"`
+	IgnorePostCommitHooks bool     `xml:"ignorePostCommitHooks"`
+}
+
+// PipelineTriggersJobProperty holds triggers of a pipeline job (properties)
+type PipelineTriggersJobProperty struct {
+	XMLName  xml.Name    `xml:"org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty"`
+	Triggers XMLElements `xml:"triggers"`
+}
+
+// ParametersDefinitionProperty declares build parameters (properties);
+// use typed *ParameterDefinitionConfig to fill ParameterDefinitions
+type ParametersDefinitionProperty struct {
+	XMLName              xml.Name    `xml:"hudson.model.ParametersDefinitionProperty"`
+	ParameterDefinitions XMLElements `xml:"parameterDefinitions"`
+}
+
+// StringParameterDefinitionConfig declares string parameter
+type StringParameterDefinitionConfig struct {
+	XMLName      xml.Name `xml:"hudson.model.StringParameterDefinition"`
+	Name         string   `xml:"name"`
+	Description  string   `xml:"description,omitempty"`
+	DefaultValue string   `xml:"defaultValue"`
+	Trim         bool     `xml:"trim"`
+}
+
+// TextParameterDefinitionConfig declares multiline string parameter
+type TextParameterDefinitionConfig struct {
+	XMLName      xml.Name `xml:"hudson.model.TextParameterDefinition"`
+	Name         string   `xml:"name"`
+	Description  string   `xml:"description,omitempty"`
+	DefaultValue string   `xml:"defaultValue"`
+	Trim         bool     `xml:"trim"`
+}
+
+// BooleanParameterDefinitionConfig declares boolean parameter
+type BooleanParameterDefinitionConfig struct {
+	XMLName      xml.Name `xml:"hudson.model.BooleanParameterDefinition"`
+	Name         string   `xml:"name"`
+	Description  string   `xml:"description,omitempty"`
+	DefaultValue bool     `xml:"defaultValue"`
+}
+
+// PasswordParameterDefinitionConfig declares password parameter;
+// DefaultValue is encrypted by Jenkins when configuration is read back
+type PasswordParameterDefinitionConfig struct {
+	XMLName      xml.Name `xml:"hudson.model.PasswordParameterDefinition"`
+	Name         string   `xml:"name"`
+	Description  string   `xml:"description,omitempty"`
+	DefaultValue string   `xml:"defaultValue"`
+}
+
+// FileParameterDefinitionConfig declares file parameter
+type FileParameterDefinitionConfig struct {
+	XMLName     xml.Name `xml:"hudson.model.FileParameterDefinition"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"description,omitempty"`
+}
+
+// ChoiceParameterDefinitionConfig declares choice parameter
+type ChoiceParameterDefinitionConfig struct {
+	XMLName     xml.Name `xml:"hudson.model.ChoiceParameterDefinition"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"description,omitempty"`
+	Choices     struct {
+		Class string `xml:"class,attr"`
+		List  struct {
+			Class   string   `xml:"class,attr"`
+			Strings []string `xml:"string"`
+		} `xml:"a"`
+	} `xml:"choices"`
+}
+
+// NewChoiceParameterDefinitionConfig declares choice parameter with given choices;
+// the first one is the default
+func NewChoiceParameterDefinitionConfig(name, description string, choices ...string) *ChoiceParameterDefinitionConfig {
+	definition := &ChoiceParameterDefinitionConfig{Name: name, Description: description}
+	definition.Choices.Class = "java.util.Arrays$ArrayList"
+	definition.Choices.List.Class = "string-array"
+	definition.Choices.List.Strings = choices
+	return definition
+}
+
+// unmarshalJobConfig decodes config.xml skipping XML declaration:
+// Jenkins declares XML 1.1 which is not supported by encoding/xml
+func unmarshalJobConfig(config string, receiver interface{}) error {
+	config = strings.TrimSpace(config)
+	if strings.HasPrefix(config, "<?xml") {
+		if end := strings.Index(config, "?>"); end >= 0 {
+			config = config[end+2:]
+		}
+	}
+	return xml.Unmarshal([]byte(config), receiver)
+}
+
+func marshalJobConfig(config interface{}) (string, error) {
+	data, err := xml.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,76 @@
+package jenkins
+
+import (
+	"net/url"
+	"strings"
+)
+
+// JobPath is a full name of a job: names of enclosing folders
+// and the name of the job itself separated by slashes ("folder/subfolder/job")
+type JobPath string
+
+// NewJobPath joins names of folders and job into JobPath
+func NewJobPath(names ...string) JobPath {
+	return JobPath(strings.Join(names, "/"))
+}
+
+// Segments returns names of enclosing folders followed by the name of the job
+func (p JobPath) Segments() []string {
+	var segments []string
+	for _, segment := range strings.Split(string(p), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// Name returns short name of the job
+func (p JobPath) Name() string {
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return ""
+	}
+	return segments[len(segments)-1]
+}
+
+// Parent returns path of the enclosing folder (empty for top-level jobs)
+func (p JobPath) Parent() JobPath {
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return ""
+	}
+	return NewJobPath(segments[:len(segments)-1]...)
+}
+
+// Route converts path into API route ("/job/folder/job/subfolder/job/job");
+// empty path corresponds to Jenkins root
+func (p JobPath) Route() string {
+	var route strings.Builder
+	for _, segment := range p.Segments() {
+		route.WriteString("/job/")
+		route.WriteString(url.PathEscape(segment))
+	}
+	return route.String()
+}
+
+// jobPathFromURL extracts JobPath from absolute URL of a job or a build
+// ("http://localhost:8080/job/folder/job/job/1/" turns into "folder/job")
+func jobPathFromURL(absoluteURL string) (JobPath, error) {
+	parsed, err := url.Parse(absoluteURL)
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "job" {
+			names = append(names, segments[i+1])
+			i++
+		} else if len(names) != 0 {
+			break
+		}
+	}
+	return NewJobPath(names...), nil
+}
@@ -0,0 +1,117 @@
+package jenkins
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Classes of items related to multibranch pipelines
+const (
+	// MultiBranchProjectClass is a class of multibranch pipeline
+	MultiBranchProjectClass = "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject"
+	// OrganizationFolderClass is a class of organization folder (i. e. GitHub organization)
+	OrganizationFolderClass = "jenkins.branch.OrganizationFolder"
+	// WorkflowJobClass is a class of pipeline job, including branches of multibranch pipelines
+	WorkflowJobClass = "org.jenkinsci.plugins.workflow.job.WorkflowJob"
+)
+
+// MultiBranchProject represents multibranch pipeline or organization folder;
+// Jobs are branch pipelines for the former and multibranch pipelines for the latter
+type MultiBranchProject struct {
+	Class        string     `json:"_class"`
+	Name         string     `json:"name"`
+	FullName     string     `json:"fullName"`
+	DisplayName  string     `json:"displayName"`
+	Description  string     `json:"description"`
+	URL          string     `json:"url"`
+	Color        string     `json:"color"`
+	Buildable    bool       `json:"buildable"`
+	Disabled     bool       `json:"disabled"`
+	Jobs         []JobBrief `json:"jobs"`
+	HealthReport []struct {
+		Description string `json:"description"`
+		Score       int    `json:"score"`
+	} `json:"healthReport"`
+	Views []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"views"`
+}
+
+// IsOrganizationFolder tells whether project is organization folder
+func (p *MultiBranchProject) IsOrganizationFolder() bool {
+	return p.Class == OrganizationFolderClass
+}
+
+// scanRoute returns route of branch indexing (organization scanning) of a project
+func (p *MultiBranchProject) scanRoute() string {
+	path := JobPath(p.FullName)
+	if p.IsOrganizationFolder() {
+		return path.Route() + "/computation"
+	}
+	return path.Route() + "/indexing"
+}
+
+// BranchKind enumerates kinds of multibranch pipeline children
+type BranchKind uint
+
+const (
+	// BranchKindBranch is a regular branch
+	BranchKindBranch BranchKind = iota
+	// BranchKindChangeRequest is a pull (merge) request
+	BranchKindChangeRequest
+	// BranchKindTag is a tag
+	BranchKindTag
+)
+
+func (k BranchKind) String() string {
+	switch k {
+	case BranchKindBranch:
+		return "branch"
+	case BranchKindChangeRequest:
+		return "change request"
+	case BranchKindTag:
+		return "tag"
+	default:
+		return "unknown"
+	}
+}
+
+// branchKindViews maps names of views created by Branch API plugin to kinds of branches
+var branchKindViews = map[string]BranchKind{
+	"change-requests": BranchKindChangeRequest,
+	"tags":            BranchKindTag,
+}
+
+// Branch is a pipeline job created by multibranch project for a branch, tag or pull request
+type Branch struct {
+	JobBrief
+	Kind BranchKind `json:"-"`
+	// DisplayName holds decoded branch name (i. e. "feature/x" for job named "feature%2Fx")
+	// or pull request title
+	DisplayName string `json:"displayName"`
+}
+
+// BranchJobPath returns path of a job created for a given branch by multibranch project;
+// branch name is encoded the same way as Branch API plugin does ("feature/x" turns into "feature%2Fx")
+func BranchJobPath(project JobPath, branch string) JobPath {
+	return NewJobPath(append(project.Segments(), encodeBranchName(branch))...)
+}
+
+func encodeBranchName(branch string) string {
+	switch branch {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	var encoded strings.Builder
+	for _, r := range branch {
+		if strings.ContainsRune(`%/\:*?"<>|`, r) {
+			encoded.WriteString(fmt.Sprintf("%%%02X", r))
+		} else {
+			encoded.WriteRune(r)
+		}
+	}
+	return encoded.String()
+}
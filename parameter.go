@@ -0,0 +1,373 @@
+package jenkins
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BuildParameterKind enumerates kinds of Jenkins build parameters
+type BuildParameterKind uint
+
+const (
+	// BuildParameterUnknown is used for parameters provided by plugins unknown to this library
+	BuildParameterUnknown BuildParameterKind = iota
+	// BuildParameterString is a single line string parameter
+	BuildParameterString
+	// BuildParameterText is a multiline string parameter
+	BuildParameterText
+	// BuildParameterBoolean is a boolean parameter
+	BuildParameterBoolean
+	// BuildParameterChoice is a string parameter with a fixed list of allowed values
+	BuildParameterChoice
+	// BuildParameterPassword is a secret string parameter
+	BuildParameterPassword
+	// BuildParameterFile is a file uploaded to the workspace
+	BuildParameterFile
+	// BuildParameterRun is a reference to a build of another job
+	BuildParameterRun
+)
+
+var buildParameterKindNames = map[string]BuildParameterKind{
+	"String":   BuildParameterString,
+	"Text":     BuildParameterText,
+	"Boolean":  BuildParameterBoolean,
+	"Choice":   BuildParameterChoice,
+	"Password": BuildParameterPassword,
+	"File":     BuildParameterFile,
+	"Run":      BuildParameterRun,
+}
+
+func (k BuildParameterKind) String() string {
+	for name, kind := range buildParameterKindNames {
+		if kind == k {
+			return name
+		}
+	}
+	return "Unknown"
+}
+
+// parseBuildParameterKind recognizes parameter kind by Jenkins class name
+// of either parameter value ("hudson.model.StringParameterValue")
+// or parameter definition ("StringParameterDefinition")
+func parseBuildParameterKind(class string) BuildParameterKind {
+	if i := strings.LastIndex(class, "."); i >= 0 {
+		class = class[i+1:]
+	}
+	class = strings.TrimSuffix(class, "ParameterValue")
+	class = strings.TrimSuffix(class, "ParameterDefinition")
+	if kind, ok := buildParameterKindNames[class]; ok {
+		return kind
+	}
+	return BuildParameterUnknown
+}
+
+// BuildParameter represents parameter of a build: either the one
+// read back from BuildAction.Parameters or the one passed to BuildInvokeWithParams
+type BuildParameter struct {
+	Kind BuildParameterKind
+	Name string
+	// Value is a string for string, text, choice, password and run parameters
+	// (the latter in "job#number" form), and a bool for boolean parameters;
+	// Jenkins doesn't return values of password and file parameters, so Value is nil for them
+	Value interface{}
+	// FileName and File are used only for uploading file parameters
+	FileName string
+	File     io.Reader
+}
+
+// JenkinsTree selects fields decoded by UnmarshalJSON
+func (p *BuildParameter) JenkinsTree() *Tree {
+	return NewTree("_class", "name", "value", "jobName", "number")
+}
+
+// UnmarshalJSON decodes parameter value from Jenkins API response
+func (p *BuildParameter) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Class string      `json:"_class"`
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+		// Run parameters export referenced build instead of value
+		JobName string      `json:"jobName"`
+		Number  json.Number `json:"number"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	p.Name = raw.Name
+	p.Value = raw.Value
+	p.Kind = parseBuildParameterKind(raw.Class)
+	if p.Kind == BuildParameterRun && raw.JobName != "" {
+		p.Value = fmt.Sprintf("%s#%s", raw.JobName, raw.Number)
+	}
+
+	// Older Jenkins versions omit class names
+	if raw.Class == "" {
+		switch raw.Value.(type) {
+		case bool:
+			p.Kind = BuildParameterBoolean
+		case string:
+			p.Kind = BuildParameterString
+		}
+	}
+	return nil
+}
+
+// StringValue returns string representation of the parameter value
+func (p BuildParameter) StringValue() string {
+	switch value := p.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// BoolValue returns value of the boolean parameter
+func (p BuildParameter) BoolValue() bool {
+	switch value := p.Value.(type) {
+	case bool:
+		return value
+	case string:
+		parsed, _ := strconv.ParseBool(value)
+		return parsed
+	default:
+		return false
+	}
+}
+
+// NewStringParameter creates string parameter
+func NewStringParameter(name, value string) BuildParameter {
+	return BuildParameter{Kind: BuildParameterString, Name: name, Value: value}
+}
+
+// NewTextParameter creates multiline string parameter
+func NewTextParameter(name, value string) BuildParameter {
+	return BuildParameter{Kind: BuildParameterText, Name: name, Value: value}
+}
+
+// NewBooleanParameter creates boolean parameter
+func NewBooleanParameter(name string, value bool) BuildParameter {
+	return BuildParameter{Kind: BuildParameterBoolean, Name: name, Value: value}
+}
+
+// NewChoiceParameter creates choice parameter
+func NewChoiceParameter(name, value string) BuildParameter {
+	return BuildParameter{Kind: BuildParameterChoice, Name: name, Value: value}
+}
+
+// NewPasswordParameter creates password parameter
+func NewPasswordParameter(name, value string) BuildParameter {
+	return BuildParameter{Kind: BuildParameterPassword, Name: name, Value: value}
+}
+
+// NewRunParameter creates run parameter referencing build in "job#number" form
+func NewRunParameter(name, value string) BuildParameter {
+	return BuildParameter{Kind: BuildParameterRun, Name: name, Value: value}
+}
+
+// NewFileParameter creates file parameter; content is read only when build is invoked
+func NewFileParameter(name, fileName string, content io.Reader) BuildParameter {
+	return BuildParameter{Kind: BuildParameterFile, Name: name, FileName: fileName, File: content}
+}
+
+// encodeBuildParameters prepares request body for buildWithParameters endpoint:
+// url-encoded form for plain parameters and multipart form if files are present
+func encodeBuildParameters(params []BuildParameter) (body io.Reader, contentType string, err error) {
+	var hasFiles bool
+	for i := range params {
+		if params[i].Kind == BuildParameterFile {
+			hasFiles = true
+			break
+		}
+	}
+
+	if !hasFiles {
+		values := url.Values{}
+		for i := range params {
+			values.Add(params[i].Name, params[i].StringValue())
+		}
+		return strings.NewReader(values.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for i := range params {
+		param := &params[i]
+		if param.Kind != BuildParameterFile {
+			if err = writer.WriteField(param.Name, param.StringValue()); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if param.File == nil {
+			return nil, "", fmt.Errorf("File parameter %s has no content", param.Name)
+		}
+		fileName := param.FileName
+		if fileName == "" {
+			fileName = param.Name
+		}
+		var part io.Writer
+		if part, err = writer.CreateFormFile(param.Name, fileName); err != nil {
+			return nil, "", err
+		}
+		if _, err = io.Copy(part, param.File); err != nil {
+			return nil, "", err
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, writer.FormDataContentType(), nil
+}
+
+// ParameterProblemReason enumerates reasons of build parameter validation failure
+type ParameterProblemReason uint
+
+const (
+	// ParameterUnknown means that job doesn't declare parameter with such name
+	ParameterUnknown ParameterProblemReason = iota
+	// ParameterWrongType means that parameter kind or value type doesn't match definition
+	ParameterWrongType
+	// ParameterNotAllowed means that value is not in the list of choices
+	ParameterNotAllowed
+)
+
+// ParameterProblem describes single invalid build parameter
+type ParameterProblem struct {
+	Name    string
+	Reason  ParameterProblemReason
+	Message string
+}
+
+// ParameterValidationError is returned when build parameters don't match job's parameter definitions
+type ParameterValidationError struct {
+	Job      string
+	Problems []ParameterProblem
+}
+
+func (e *ParameterValidationError) Error() string {
+	messages := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		messages = append(messages, fmt.Sprintf("%s: %s", problem.Name, problem.Message))
+	}
+	return fmt.Sprintf("Invalid build parameters for job %s: %s", e.Job, strings.Join(messages, "; "))
+}
+
+// ValidateBuildParameters checks parameters against job's parameter definitions;
+// returns *ParameterValidationError describing every invalid parameter.
+// Omitted parameters are not reported: Jenkins has no required parameters and
+// uses default values (if any) instead of them
+func ValidateBuildParameters(job *Job, params []BuildParameter) error {
+	var (
+		definitions = make(map[string]*JobParameterDefinition)
+		problems    []ParameterProblem
+	)
+
+	declared := job.ParameterDefinitions()
+	for i := range declared {
+		definitions[declared[i].Name] = &declared[i]
+	}
+
+	for _, param := range params {
+		definition, exists := definitions[param.Name]
+		if !exists {
+			problems = append(problems, ParameterProblem{
+				Name:    param.Name,
+				Reason:  ParameterUnknown,
+				Message: "parameter is not declared by job",
+			})
+			continue
+		}
+		if problem := validateBuildParameter(definition, &param); problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+
+	if len(problems) != 0 {
+		return &ParameterValidationError{Job: job.Name, Problems: problems}
+	}
+	return nil
+}
+
+// kinds which values are passed as plain strings and may be given one instead of another
+var stringLikeBuildParameterKinds = map[BuildParameterKind]bool{
+	BuildParameterString: true,
+	BuildParameterText:   true,
+	BuildParameterChoice: true,
+}
+
+// compatibleBuildParameterKinds tells whether parameter of a given kind may be passed for the expected one;
+// boolean values may be given as strings as well, the value itself is checked then
+func compatibleBuildParameterKinds(given, expected BuildParameterKind) bool {
+	switch {
+	case given == BuildParameterUnknown, given == expected:
+		return true
+	case stringLikeBuildParameterKinds[given]:
+		return stringLikeBuildParameterKinds[expected] || expected == BuildParameterBoolean
+	default:
+		return false
+	}
+}
+
+func validateBuildParameter(definition *JobParameterDefinition, param *BuildParameter) *ParameterProblem {
+	expected := definition.Kind()
+	wrongType := func(message string) *ParameterProblem {
+		return &ParameterProblem{Name: param.Name, Reason: ParameterWrongType, Message: message}
+	}
+
+	// Nothing can be checked for parameters provided by unknown plugins
+	if expected == BuildParameterUnknown {
+		return nil
+	}
+
+	if !compatibleBuildParameterKinds(param.Kind, expected) {
+		return wrongType(fmt.Sprintf("expected %s parameter, got %s", expected, param.Kind))
+	}
+
+	switch expected {
+	case BuildParameterBoolean:
+		switch value := param.Value.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(value); err != nil {
+				return wrongType(fmt.Sprintf("value %q is not a boolean", value))
+			}
+		default:
+			return wrongType(fmt.Sprintf("value of type %T is not a boolean", param.Value))
+		}
+	case BuildParameterFile:
+		if param.File == nil {
+			return wrongType("file parameter has no content")
+		}
+	case BuildParameterChoice:
+		value := param.StringValue()
+		for _, choice := range definition.Choices {
+			if choice == value {
+				return nil
+			}
+		}
+		return &ParameterProblem{
+			Name:    param.Name,
+			Reason:  ParameterNotAllowed,
+			Message: fmt.Sprintf("value %q is not one of %s", value, strings.Join(definition.Choices, ", ")),
+		}
+	default:
+		switch param.Value.(type) {
+		case nil, string:
+		default:
+			return wrongType(fmt.Sprintf("value of type %T is not a string", param.Value))
+		}
+	}
+	return nil
+}
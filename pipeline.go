@@ -0,0 +1,207 @@
+package jenkins
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PipelineStatus is a status of pipeline run, stage or step reported by wfapi
+type PipelineStatus string
+
+// Pipeline statuses
+const (
+	PipelineStatusSuccess        PipelineStatus = "SUCCESS"
+	PipelineStatusFailed         PipelineStatus = "FAILED"
+	PipelineStatusUnstable       PipelineStatus = "UNSTABLE"
+	PipelineStatusAborted        PipelineStatus = "ABORTED"
+	PipelineStatusInProgress     PipelineStatus = "IN_PROGRESS"
+	PipelineStatusPausedForInput PipelineStatus = "PAUSED_PENDING_INPUT"
+	PipelineStatusNotExecuted    PipelineStatus = "NOT_EXECUTED"
+	PipelineStatusQueued         PipelineStatus = "QUEUED"
+)
+
+// PipelineTiming holds timestamps and durations (in milliseconds) common to wfapi entities
+type PipelineTiming struct {
+	StartTimeMillis     int64 `json:"startTimeMillis"`
+	EndTimeMillis       int64 `json:"endTimeMillis"`
+	DurationMillis      int64 `json:"durationMillis"`
+	PauseDurationMillis int64 `json:"pauseDurationMillis"`
+}
+
+// StartTime returns the moment when the entity has been started
+func (t *PipelineTiming) StartTime() time.Time {
+	return time.Unix(0, t.StartTimeMillis*int64(time.Millisecond))
+}
+
+// Duration returns time spent by entity, including pauses
+func (t *PipelineTiming) Duration() time.Duration {
+	return time.Duration(t.DurationMillis) * time.Millisecond
+}
+
+// PauseDuration returns time spent by entity waiting for input or executors
+func (t *PipelineTiming) PauseDuration() time.Duration {
+	return time.Duration(t.PauseDurationMillis) * time.Millisecond
+}
+
+// PipelineError describes failure of a stage or a step
+type PipelineError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// PipelineRun is a pipeline build description returned by /wfapi/describe
+type PipelineRun struct {
+	PipelineTiming
+	ID                  string          `json:"id"`
+	Name                string          `json:"name"`
+	Status              PipelineStatus  `json:"status"`
+	QueueDurationMillis int64           `json:"queueDurationMillis"`
+	Stages              []PipelineStage `json:"stages"`
+}
+
+// FailedStage returns the first stage that has not succeeded, if any
+func (r *PipelineRun) FailedStage() *PipelineStage {
+	for i := range r.Stages {
+		switch r.Stages[i].Status {
+		case PipelineStatusFailed, PipelineStatusUnstable, PipelineStatusAborted:
+			return &r.Stages[i]
+		}
+	}
+	return nil
+}
+
+// PipelineStage describes stage of a pipeline run; StageFlowNodes (steps of the stage)
+// are filled only when the stage node is described with PipelineNodeDescribe
+type PipelineStage struct {
+	PipelineTiming
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	ExecNode       string         `json:"execNode"`
+	Status         PipelineStatus `json:"status"`
+	Error          *PipelineError `json:"error"`
+	StageFlowNodes []PipelineNode `json:"stageFlowNodes"`
+}
+
+// PipelineNode describes step of a pipeline stage
+type PipelineNode struct {
+	PipelineTiming
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name"`
+	ExecNode             string         `json:"execNode"`
+	Status               PipelineStatus `json:"status"`
+	ParameterDescription string         `json:"parameterDescription"`
+	Error                *PipelineError `json:"error"`
+	ParentNodes          []string       `json:"parentNodes"`
+}
+
+// PipelineNodeLog is a console output of a single pipeline step
+type PipelineNodeLog struct {
+	NodeID     string         `json:"nodeId"`
+	NodeStatus PipelineStatus `json:"nodeStatus"`
+	Length     int64          `json:"length"`
+	HasMore    bool           `json:"hasMore"`
+	Text       string         `json:"text"`
+	ConsoleURL string         `json:"consoleUrl"`
+}
+
+// PipelineInput is an input step waiting for user's decision
+type PipelineInput struct {
+	ID                  string                   `json:"id"`
+	Message             string                   `json:"message"`
+	ProceedText         string                   `json:"proceedText"`
+	Inputs              []PipelineInputParameter `json:"inputs"`
+	ProceedURL          string                   `json:"proceedUrl"`
+	AbortURL            string                   `json:"abortUrl"`
+	RedirectApprovalURL string                   `json:"redirectApprovalUrl"`
+}
+
+// PipelineInputParameter describes parameter requested by input step
+type PipelineInputParameter struct {
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Definition  map[string]interface{} `json:"definition"`
+}
+
+// Kind returns kind of the requested parameter
+func (p *PipelineInputParameter) Kind() BuildParameterKind {
+	return parseBuildParameterKind(p.Type)
+}
+
+// encodeInputParameters prepares form submitted to input step
+func encodeInputParameters(proceedText string, params []BuildParameter) (url.Values, error) {
+	type parameter struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	}
+	var submitted struct {
+		Parameter []parameter `json:"parameter"`
+	}
+	for _, param := range params {
+		submitted.Parameter = append(submitted.Parameter, parameter{Name: param.Name, Value: param.Value})
+	}
+	encoded, err := json.Marshal(&submitted)
+	if err != nil {
+		return nil, err
+	}
+	// Jenkins treats submission without "proceed" field as abortion
+	return url.Values{
+		"proceed": []string{proceedText},
+		"json":    []string{string(encoded)},
+	}, nil
+}
+
+// PipelineDiagnostic is a problem found in Jenkinsfile by declarative pipeline linter;
+// Line and Column are zero for problems not bound to a particular place
+type PipelineDiagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d PipelineDiagnostic) String() string {
+	if d.Line == 0 {
+		return d.Message
+	}
+	return fmt.Sprintf("line %d, column %d: %s", d.Line, d.Column, d.Message)
+}
+
+const (
+	pipelineValidationSucceeded = "Jenkinsfile successfully validated."
+	pipelineValidationFailed    = "Errors encountered validating Jenkinsfile:"
+)
+
+// Groovy compilation errors look like
+// "WorkflowScript: 3: Expected a stage @ line 3, column 5." followed by the source snippet
+var pipelineCompilationError = regexp.MustCompile(`(?s)WorkflowScript: \d+: (.*?) @ line (\d+), column (\d+)\.`)
+
+// parsePipelineValidation extracts diagnostics from plain text linter output
+func parsePipelineValidation(output string) []PipelineDiagnostic {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, pipelineValidationSucceeded) {
+		return nil
+	}
+	output = strings.TrimSpace(strings.TrimPrefix(output, pipelineValidationFailed))
+
+	var diagnostics []PipelineDiagnostic
+	for _, match := range pipelineCompilationError.FindAllStringSubmatch(output, -1) {
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		diagnostics = append(diagnostics, PipelineDiagnostic{
+			Line:    line,
+			Column:  column,
+			Message: strings.TrimSpace(match[1]),
+		})
+	}
+
+	// Some errors (i. e. missing pipeline block) are not bound to the source position
+	if len(diagnostics) == 0 {
+		diagnostics = append(diagnostics, PipelineDiagnostic{Message: output})
+	}
+	return diagnostics
+}
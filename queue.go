@@ -1,5 +1,7 @@
 package jenkins
 
+import "time"
+
 // Queue contains list of invoked builds waiting for available executor
 type Queue struct {
 	Items []QueueItem `json:"items"`
@@ -7,20 +9,92 @@ type Queue struct {
 
 // QueueItem represents invoked build
 type QueueItem struct {
-	Actions                    []struct{} `json:"actions"`
-	Blocked                    bool       `json:"blocked"`
-	Buildable                  bool       `json:"buildable"`
-	BuildableStartMilliseconds int        `json:"buildableStartMilliseconds"`
-	ID                         int        `json:"id"`
-	InQueueSince               int        `json:"inQueueSince"`
-	Params                     string     `json:"params"`
-	Pending                    bool       `json:"pending"`
-	Stuck                      bool       `json:"stuck"`
-	Task                       struct {
-		Color string `json:"color"`
-		Name  string `json:"name"`
-		URL   string `json:"url"`
-	} `json:"task"`
-	URL string `json:"url"`
-	Why string `json:"why"`
+	// Class is one of hudson.model.Queue$WaitingItem, $BlockedItem, $BuildableItem
+	// for items in queue, and hudson.model.Queue$LeftItem for items that have left it
+	Class                      string               `json:"_class"`
+	Actions                    []BuildAction        `json:"actions"`
+	Blocked                    bool                 `json:"blocked"`
+	Buildable                  bool                 `json:"buildable"`
+	BuildableStartMilliseconds int64                `json:"buildableStartMilliseconds"`
+	Cancelled                  bool                 `json:"cancelled"`
+	Executable                 *QueueItemExecutable `json:"executable"`
+	ID                         int                  `json:"id"`
+	InQueueSince               int64                `json:"inQueueSince"`
+	Params                     string               `json:"params"`
+	Pending                    bool                 `json:"pending"`
+	Stuck                      bool                 `json:"stuck"`
+	Task                       QueueItemTask        `json:"task"`
+	Timestamp                  int64                `json:"timestamp"`
+	URL                        string               `json:"url"`
+	Why                        string               `json:"why"`
+}
+
+// QueueItemTask refers to the job which build is waiting in the queue
+type QueueItemTask struct {
+	Class string `json:"_class"`
+	Color string `json:"color"`
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+}
+
+// QueueItemExecutable refers to the build started from the queue item;
+// it is set only when the item has left the queue
+type QueueItemExecutable struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+// InQueueSinceTime returns the moment when the item has been put into the queue
+func (i *QueueItem) InQueueSinceTime() time.Time {
+	return time.Unix(0, i.InQueueSince*int64(time.Millisecond))
+}
+
+// QueueFilter is a predicate used to select queue items
+type QueueFilter func(*QueueItem) bool
+
+// Filter returns queue items matching all the given filters
+func (q *Queue) Filter(filters ...QueueFilter) []QueueItem {
+	var result []QueueItem
+	for i := range q.Items {
+		matched := true
+		for _, filter := range filters {
+			if !filter(&q.Items[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, q.Items[i])
+		}
+	}
+	return result
+}
+
+// QueueFilterByJob selects items of a job with a given path
+func QueueFilterByJob(name JobPath) QueueFilter {
+	return func(item *QueueItem) bool {
+		taskPath, err := jobPathFromURL(item.Task.URL)
+		return err == nil && taskPath.Route() == name.Route()
+	}
+}
+
+// QueueFilterBlocked selects items blocked by other builds or by job's settings
+func QueueFilterBlocked() QueueFilter {
+	return func(item *QueueItem) bool {
+		return item.Blocked
+	}
+}
+
+// QueueFilterStuck selects items that cannot be built because of the lack of suitable executors
+func QueueFilterStuck() QueueFilter {
+	return func(item *QueueItem) bool {
+		return item.Stuck
+	}
+}
+
+// QueueFilterOlderThan selects items waiting in the queue longer than a given duration
+func QueueFilterOlderThan(age time.Duration) QueueFilter {
+	return func(item *QueueItem) bool {
+		return time.Since(item.InQueueSinceTime()) > age
+	}
 }
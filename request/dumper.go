@@ -3,6 +3,7 @@ package request
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -19,6 +20,12 @@ const (
 	ResponseDumpDefaultJSON
 	// ResponseDumpHeaderLocation dumps Location response header
 	ResponseDumpHeaderLocation
+	// ResponseDumpRaw copies successful (2xx) response into a given *http.Response;
+	// response body is left open and must be closed by caller
+	ResponseDumpRaw
+	// ResponseDumpDefaultXML unmarshalles XML into a given struct
+	// (or copies plain text into a given *string)
+	ResponseDumpDefaultXML
 )
 
 // Jenkins API may answer you in many different ways;
@@ -29,12 +36,22 @@ type dumper struct {
 
 func (dm *dumper) dump(httpResponse *http.Response, receiver interface{}, method ResponseDumpMethod) error {
 
+	// Raw response body is owned by receiver
+	if method == ResponseDumpRaw {
+		return dm.raw(httpResponse, receiver)
+	}
+
+	defer httpResponse.Body.Close()
+
 	// Select dump method and run it
 	switch method {
 	case ResponseDumpNone:
-		return nil
+		// Any successful status is fine here
+		return checkResponseSuccess(httpResponse)
 	case ResponseDumpDefaultJSON:
 		return dm.defaultJSON(httpResponse, receiver)
+	case ResponseDumpDefaultXML:
+		return dm.defaultXML(httpResponse, receiver)
 	case ResponseDumpHeaderLocation:
 		// Cast receiver to URL
 		var (
@@ -51,15 +68,30 @@ func (dm *dumper) dump(httpResponse *http.Response, receiver interface{}, method
 	}
 }
 
+// Pass response to a caller as is
+func (dm *dumper) raw(httpResponse *http.Response, receiver interface{}) error {
+	receiverResponse, casted := receiver.(*http.Response)
+	if !casted {
+		httpResponse.Body.Close()
+		return fmt.Errorf("Cannot cast receiver to *http.Response")
+	}
+
+	// Check response status
+	if err := checkResponseSuccess(httpResponse); err != nil {
+		httpResponse.Body.Close()
+		return err
+	}
+
+	*receiverResponse = *httpResponse
+	return nil
+}
+
 // Unmarshal location header to a given URL
 func (dm *dumper) headerLocation(httpResponse *http.Response, receiver *url.URL) error {
 
 	// Check response status
-	switch httpResponse.StatusCode {
-	case http.StatusCreated:
-		break
-	default:
-		return fmt.Errorf("Bad response status: %s", httpResponse.Status)
+	if err := checkResponseStatus(httpResponse, http.StatusCreated); err != nil {
+		return err
 	}
 
 	location, err := httpResponse.Location()
@@ -74,15 +106,11 @@ func (dm *dumper) headerLocation(httpResponse *http.Response, receiver *url.URL)
 func (dm *dumper) defaultJSON(httpResponse *http.Response, receiver interface{}) error {
 
 	// Check response status
-	switch httpResponse.StatusCode {
-	case http.StatusOK:
-		break
-	default:
-		return fmt.Errorf("Bad response status: %s", httpResponse.Status)
+	if err := checkResponseStatus(httpResponse, http.StatusOK); err != nil {
+		return err
 	}
 
 	var err error
-	defer httpResponse.Body.Close()
 
 	switch dm.debug {
 	case true:
@@ -114,3 +142,32 @@ func (dm *dumper) defaultJSON(httpResponse *http.Response, receiver interface{})
 	}
 	return err
 }
+
+// Unmarshal XML to a given receiver; XPath queries selecting text
+// are answered with plain text which can be dumped only to *string
+func (dm *dumper) defaultXML(httpResponse *http.Response, receiver interface{}) error {
+
+	// Check response status
+	if err := checkResponseStatus(httpResponse, http.StatusOK); err != nil {
+		return err
+	}
+
+	dumpedBody, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+	if dm.debug {
+		// FIXME: use logger
+		fmt.Printf("ResponseBody: %s\n", string(dumpedBody))
+	}
+
+	switch typed := receiver.(type) {
+	case nil:
+		return nil
+	case *string:
+		*typed = string(dumpedBody)
+		return nil
+	default:
+		return xml.Unmarshal(dumpedBody, receiver)
+	}
+}
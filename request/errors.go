@@ -0,0 +1,102 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matching the most common Jenkins API failures;
+// *APIError unwraps to one of them, so they can be checked with errors.Is
+var (
+	// ErrBadRequest is returned when Jenkins rejects request parameters (400)
+	ErrBadRequest = errors.New("jenkins: bad request")
+	// ErrUnauthorized is returned when credentials are missing or wrong (401)
+	ErrUnauthorized = errors.New("jenkins: unauthorized")
+	// ErrForbidden is returned when user has no permission for the requested action (403)
+	ErrForbidden = errors.New("jenkins: forbidden")
+	// ErrCrumbInvalid is returned when Jenkins CSRF protection rejects the request (403)
+	ErrCrumbInvalid = errors.New("jenkins: no valid crumb was included in the request")
+	// ErrNotFound is returned when requested item does not exist (404)
+	ErrNotFound = errors.New("jenkins: not found")
+	// ErrConflict is returned when request conflicts with the current state of the item (409)
+	ErrConflict = errors.New("jenkins: conflict")
+)
+
+// apiErrorBodyLimit is the maximum length of response body snippet kept in APIError
+const apiErrorBodyLimit = 512
+
+// APIError describes unexpected HTTP response from Jenkins API
+type APIError struct {
+	StatusCode int
+	Status     string
+	Method     string
+	URL        string
+	// Body contains the beginning of the response body
+	Body string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Bad response status: %s (%s %s)", e.Status, e.Method, e.URL)
+}
+
+// Unwrap returns sentinel error corresponding to the response status
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		// Jenkins uses 403 both for permission and CSRF failures
+		if strings.Contains(e.Body, "No valid crumb") {
+			return ErrCrumbInvalid
+		}
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	default:
+		return nil
+	}
+}
+
+// newAPIError builds APIError from a given response, consuming part of its body
+func newAPIError(httpResponse *http.Response) *APIError {
+	apiError := &APIError{
+		StatusCode: httpResponse.StatusCode,
+		Status:     httpResponse.Status,
+	}
+	if httpResponse.Request != nil {
+		apiError.Method = httpResponse.Request.Method
+		apiError.URL = httpResponse.Request.URL.String()
+	}
+	if httpResponse.Body != nil {
+		snippet, _ := ioutil.ReadAll(io.LimitReader(httpResponse.Body, apiErrorBodyLimit))
+		apiError.Body = string(snippet)
+	}
+	return apiError
+}
+
+// checkResponseStatus returns APIError if response status is not
+// among the expected ones
+func checkResponseStatus(httpResponse *http.Response, expected ...int) error {
+	for _, status := range expected {
+		if httpResponse.StatusCode == status {
+			return nil
+		}
+	}
+	return newAPIError(httpResponse)
+}
+
+// checkResponseSuccess returns APIError if response status is not 2xx
+func checkResponseSuccess(httpResponse *http.Response) error {
+	if httpResponse.StatusCode < http.StatusOK || httpResponse.StatusCode >= http.StatusMultipleChoices {
+		return newAPIError(httpResponse)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package request
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 )
 
 // JenkinsAPIRequest instances are passed to requester from
@@ -13,8 +15,37 @@ type JenkinsAPIRequest struct {
 	Route       string
 	Body        io.Reader
 	QueryParams map[string]string
+	// Query holds parameters that may be repeated; they are added to QueryParams
+	Query url.Values
+	// ContentType is set as a Content-Type header of request if not empty
+	ContentType string
 	Format      JenkinsAPIFormat
-	DumpMethod  ResponseDumpMethod
+	// XMLQuery filters responses of requests with JenkinsAPIFormatXML
+	XMLQuery   *XMLQuery
+	DumpMethod ResponseDumpMethod
+}
+
+// XMLQuery holds parameters of server-side filtering supported by /api/xml
+type XMLQuery struct {
+	// XPath selects nodes of the response (i. e. "//build[result='FAILURE']/number");
+	// text results (i. e. "/freeStyleProject/name/text()") are returned as plain text
+	XPath string
+	// Exclude removes nodes matching any of the given expressions
+	Exclude []string
+	// Wrapper is a name of the root element wrapping multiple nodes selected by XPath
+	Wrapper string
+}
+
+func (q *XMLQuery) apply(query url.Values) {
+	if q.XPath != "" {
+		query.Set("xpath", q.XPath)
+	}
+	for _, exclude := range q.Exclude {
+		query.Add("exclude", exclude)
+	}
+	if q.Wrapper != "" {
+		query.Set("wrapper", q.Wrapper)
+	}
 }
 
 // JenkinsAPIFormat turns on JSON or XML responses from Jenkins API
@@ -25,6 +56,8 @@ const (
 	JenkinsAPIFormatJSON JenkinsAPIFormat = iota
 	// JenkinsAPIFormatXML appends /api/xml to request routes
 	JenkinsAPIFormatXML
+	// JenkinsAPIFormatNone leaves request routes untouched (i. e. for /consoleText)
+	JenkinsAPIFormatNone
 )
 
 type fabric struct {
@@ -40,37 +73,51 @@ func (rf *fabric) newURLString(route string, format JenkinsAPIFormat) string {
 		URL = fmt.Sprintf("%s%s/api/xml", rf.baseURL, route)
 	case JenkinsAPIFormatJSON:
 		URL = fmt.Sprintf("%s%s/api/json", rf.baseURL, route)
+	case JenkinsAPIFormatNone:
+		URL = fmt.Sprintf("%s%s", rf.baseURL, route)
 	}
 	return URL
 }
 
 // Creates arbitrary HTTP Request
-func (rf *fabric) newHTTPRequest(apiRequest *JenkinsAPIRequest) (*http.Request, error) {
+func (rf *fabric) newHTTPRequest(ctx context.Context, apiRequest *JenkinsAPIRequest) (*http.Request, error) {
 	// Create URL base
 	URL := rf.newURLString(apiRequest.Route, apiRequest.Format)
 
-	httpRequest, err := http.NewRequest(apiRequest.Method, URL, apiRequest.Body)
+	httpRequest, err := http.NewRequestWithContext(ctx, apiRequest.Method, URL, apiRequest.Body)
 	if err != nil {
 		return nil, err
 	}
 
 	// Build query params
-	if apiRequest.QueryParams != nil {
+	if apiRequest.QueryParams != nil || apiRequest.Query != nil || apiRequest.XMLQuery != nil {
 		query := httpRequest.URL.Query()
 		for key, value := range apiRequest.QueryParams {
 			query.Add(key, value)
 		}
+		for key, values := range apiRequest.Query {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+		if apiRequest.XMLQuery != nil && apiRequest.Format == JenkinsAPIFormatXML {
+			apiRequest.XMLQuery.apply(query)
+		}
 		httpRequest.URL.RawQuery = query.Encode()
 	}
 
+	if apiRequest.ContentType != "" {
+		httpRequest.Header.Set("Content-Type", apiRequest.ContentType)
+	}
+
 	httpRequest.SetBasicAuth(rf.username, rf.password)
 	return httpRequest, nil
 }
 
 // Creates new HTTP Request used for crumb generation
-func (rf *fabric) newCrumbRequest() (*http.Request, error) {
+func (rf *fabric) newCrumbRequest(ctx context.Context) (*http.Request, error) {
 	URL := rf.newURLString("/crumbIssuer", JenkinsAPIFormatJSON)
-	httpRequest, err := http.NewRequest("GET", URL, nil)
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", URL, nil)
 	if err != nil {
 		return nil, err
 	}
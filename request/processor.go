@@ -10,10 +10,12 @@ import (
 )
 
 // Processor wraps routines related to the HTTP layer of interaction with Jenkins API
+// (context is respected during the whole request lifecycle, including crumb generation);
+// Do performs request with any method, GetJSON and PostXML set content type of the request as well
 type Processor interface {
-	GetJSON(*JenkinsAPIRequest, interface{}) error
-	Post(*JenkinsAPIRequest, interface{}) error
-	PostXML(*JenkinsAPIRequest, interface{}) error
+	Do(context.Context, *JenkinsAPIRequest, interface{}) error
+	GetJSON(context.Context, *JenkinsAPIRequest, interface{}) error
+	PostXML(context.Context, *JenkinsAPIRequest, interface{}) error
 }
 
 type defaultProcessor struct {
@@ -23,45 +25,45 @@ type defaultProcessor struct {
 	debug  bool
 }
 
-func (p *defaultProcessor) GetJSON(apiRequest *JenkinsAPIRequest, receiver interface{}) error {
-	httpRequest, err := p.fb.newHTTPRequest(apiRequest)
+func (p *defaultProcessor) Do(ctx context.Context, apiRequest *JenkinsAPIRequest, receiver interface{}) error {
+	httpRequest, err := p.fb.newHTTPRequest(ctx, apiRequest)
 	if err != nil {
 		return err
 	}
-	httpRequest.Header.Add("Content-Type", "application/json")
-	return p.call(httpRequest, receiver, apiRequest.DumpMethod, true)
+	return p.call(ctx, httpRequest, receiver, apiRequest.DumpMethod, true)
 }
 
-func (p *defaultProcessor) Post(apiRequest *JenkinsAPIRequest, receiver interface{}) error {
-	httpRequest, err := p.fb.newHTTPRequest(apiRequest)
+func (p *defaultProcessor) GetJSON(ctx context.Context, apiRequest *JenkinsAPIRequest, receiver interface{}) error {
+	httpRequest, err := p.fb.newHTTPRequest(ctx, apiRequest)
 	if err != nil {
 		return err
 	}
-	return p.call(httpRequest, receiver, apiRequest.DumpMethod, true)
+	httpRequest.Header.Add("Content-Type", "application/json")
+	return p.call(ctx, httpRequest, receiver, apiRequest.DumpMethod, true)
 }
 
-func (p *defaultProcessor) PostXML(apiRequest *JenkinsAPIRequest, receiver interface{}) error {
-	httpRequest, err := p.fb.newHTTPRequest(apiRequest)
+func (p *defaultProcessor) PostXML(ctx context.Context, apiRequest *JenkinsAPIRequest, receiver interface{}) error {
+	httpRequest, err := p.fb.newHTTPRequest(ctx, apiRequest)
 	if err != nil {
 		return err
 	}
 	httpRequest.Header.Add("Content-Type", "application/xml")
-	return p.call(httpRequest, receiver, apiRequest.DumpMethod, true)
+	return p.call(ctx, httpRequest, receiver, apiRequest.DumpMethod, true)
 }
 
 // Make HTTP Request match Jenkins CSRF protection requirements
 // (enabled by default in 2.x)
-func (p *defaultProcessor) setCrumbs(httpRequest *http.Request) error {
+func (p *defaultProcessor) setCrumbs(ctx context.Context, httpRequest *http.Request) error {
 	var err error
 	var crumbRequest *http.Request
 
-	crumbRequest, err = p.fb.newCrumbRequest()
+	crumbRequest, err = p.fb.newCrumbRequest(ctx)
 	if err != nil {
 		return err
 	}
 	receiver := make(map[string]string)
 
-	err = p.call(crumbRequest, &receiver, ResponseDumpDefaultJSON, false)
+	err = p.call(ctx, crumbRequest, &receiver, ResponseDumpDefaultJSON, false)
 	if err != nil {
 		return err
 	}
@@ -79,6 +81,7 @@ func (p *defaultProcessor) setCrumbs(httpRequest *http.Request) error {
 
 // Emit HTTP request to Jenkins endpoint and
 func (p *defaultProcessor) call(
+	ctx context.Context,
 	req *http.Request,
 	receiver interface{},
 	dumpMethod ResponseDumpMethod,
@@ -89,15 +92,21 @@ func (p *defaultProcessor) call(
 		fmt.Printf("Request URL: %s\n", req.URL)
 	}
 
+	// Do not even try to perform request if context is already done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Set header preventing CSRF attacs if necessary
 	if setCrumbs {
-		if err := p.setCrumbs(req); err != nil {
+		if err := p.setCrumbs(ctx, req); err != nil {
 			return err
 		}
 	}
 
-	// Perform HTTP request
-	resp, err := ctxhttp.Do(context.Background(), p.client, req)
+	// Perform HTTP request; ctxhttp returns ctx.Err() if context
+	// has been cancelled or its deadline has been exceeded
+	resp, err := ctxhttp.Do(ctx, p.client, req)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,69 @@
+package jenkins
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Classes of causes attached to the builds re-run from another build
+const (
+	// ReplayCauseClass is a cause of a build started with Replay action
+	ReplayCauseClass = "org.jenkinsci.plugins.workflow.cps.replay.ReplayCause"
+	// RestartCauseClass is a cause of a declarative pipeline build restarted from a stage
+	RestartCauseClass = "org.jenkinsci.plugins.pipeline.modeldefinition.causes.RestartDeclarativePipelineCause"
+)
+
+// Replay and restart actions redirect to the job page instead of returning queue item location,
+// so the queue item is looked up by its cause for a while after submission
+const (
+	rerunLookupAttempts = 10
+	rerunLookupInterval = 500 * time.Millisecond
+)
+
+// rerunCause recognizes the cause of a build re-run from the original build;
+// Jenkins exports only human readable description of these causes ("Replayed #5",
+// "Restarted from build #5, stage Build"), so the original build number is taken from it
+type rerunCause struct {
+	class  string
+	origin *regexp.Regexp
+}
+
+func newRerunCause(class string, origin int) *rerunCause {
+	return &rerunCause{
+		class:  class,
+		origin: regexp.MustCompile(fmt.Sprintf(`#%d\b`, origin)),
+	}
+}
+
+// matches checks whether any of the given actions holds the cause
+func (c *rerunCause) matches(actions []BuildAction) bool {
+	for _, action := range actions {
+		for _, cause := range action.Causes {
+			class, _ := cause["_class"].(string)
+			description, _ := cause["shortDescription"].(string)
+			if class == c.class && c.origin.MatchString(description) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// queueFilter selects queue items with the cause
+func (c *rerunCause) queueFilter() QueueFilter {
+	return func(item *QueueItem) bool {
+		return c.matches(item.Actions)
+	}
+}
+
+// rerunBuildTree selects fields of rerunBuild
+var rerunBuildTree = NewTree("number", "queueId").
+	Nested("actions", NewTree().Nested("causes", NewTree("_class", "shortDescription")))
+
+// rerunBuild is a brief description of a build used to find re-run builds in history
+type rerunBuild struct {
+	Number  int           `json:"number"`
+	QueueID int           `json:"queueId"`
+	Actions []BuildAction `json:"actions"`
+}
@@ -0,0 +1,78 @@
+package jenkins
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Script console prints stack traces of uncaught exceptions as a regular output,
+// so scripts are wrapped to tell failures and return values from the output
+const (
+	scriptFailureMarker = "<<<jenkins-client-golang: script failed>>>"
+	scriptResultMarker  = "<<<jenkins-client-golang: script result>>>"
+)
+
+// scriptWrapper evaluates base64-encoded script (so it may contain imports and any quotes)
+// in the binding of the script console with the same default imports as the console has,
+// printing the exception message after the failure marker
+const scriptWrapper = `def script = new String('%s'.decodeBase64(), 'UTF-8')
+def config = new org.codehaus.groovy.control.CompilerConfiguration()
+config.addCompilationCustomizers(new org.codehaus.groovy.control.customizers.ImportCustomizer()
+    .addStarImports('jenkins', 'jenkins.model', 'hudson', 'hudson.model'))
+def shell = new GroovyShell(this.class.classLoader, binding, config)
+def result
+try {
+    result = shell.evaluate(script)
+} catch (Throwable e) {
+    println '` + scriptFailureMarker + `'
+    print e.toString()
+    return
+}
+`
+
+// scriptJSONResult prints return value of the wrapped script serialized to JSON after the result marker
+const scriptJSONResult = `println '` + scriptResultMarker + `'
+print groovy.json.JsonOutput.toJson(result)
+`
+
+// ScriptError is returned when Groovy script throws an exception
+type ScriptError struct {
+	// Output is printed by script before the exception
+	Output string
+	// Exception is a class name and message of the exception
+	// ("groovy.lang.MissingPropertyException: No such property: x for class: Script1")
+	Exception string
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("Script failed: %s", e.Exception)
+}
+
+// Unwrap allows to check script failures with errors.Is(err, ErrScriptFailed)
+func (e *ScriptError) Unwrap() error {
+	return ErrScriptFailed
+}
+
+// wrapScript prepares script for script console, optionally appending JSON serialization of its return value
+func wrapScript(script string, withResult bool) string {
+	wrapped := fmt.Sprintf(scriptWrapper, base64.StdEncoding.EncodeToString([]byte(script)))
+	if withResult {
+		wrapped += scriptJSONResult
+	}
+	return wrapped
+}
+
+// parseScriptOutput splits output of wrapped script into the printed text and the serialized return value
+func parseScriptOutput(output string) (string, string, error) {
+	if i := strings.Index(output, scriptFailureMarker); i >= 0 {
+		return "", "", &ScriptError{
+			Output:    strings.TrimSuffix(output[:i], "\n"),
+			Exception: strings.TrimSpace(output[i+len(scriptFailureMarker):]),
+		}
+	}
+	if i := strings.LastIndex(output, scriptResultMarker); i >= 0 {
+		return strings.TrimSuffix(output[:i], "\n"), strings.TrimSpace(output[i+len(scriptResultMarker):]), nil
+	}
+	return output, "", nil
+}
@@ -0,0 +1,98 @@
+package jenkins
+
+import "time"
+
+// TestStatus is a status of a test case reported by JUnit plugin
+type TestStatus string
+
+// Test case statuses; FIXED and REGRESSION are reported instead of PASSED and FAILED
+// when the status has changed since the previous build
+const (
+	TestStatusPassed     TestStatus = "PASSED"
+	TestStatusSkipped    TestStatus = "SKIPPED"
+	TestStatusFailed     TestStatus = "FAILED"
+	TestStatusFixed      TestStatus = "FIXED"
+	TestStatusRegression TestStatus = "REGRESSION"
+)
+
+// IsFailed tells whether test case has failed
+func (s TestStatus) IsFailed() bool {
+	return s == TestStatusFailed || s == TestStatusRegression
+}
+
+// TestReport is a result of tests recorded by JUnit plugin for a build
+type TestReport struct {
+	// DurationSeconds is a total time spent by all the suites
+	DurationSeconds float64     `json:"duration"`
+	Empty           bool        `json:"empty"`
+	FailCount       int         `json:"failCount"`
+	PassCount       int         `json:"passCount"`
+	SkipCount       int         `json:"skipCount"`
+	Suites          []TestSuite `json:"suites"`
+}
+
+// Duration returns total time spent by all the suites
+func (r *TestReport) Duration() time.Duration {
+	return secondsToDuration(r.DurationSeconds)
+}
+
+// TotalCount returns number of all the test cases
+func (r *TestReport) TotalCount() int {
+	return r.FailCount + r.PassCount + r.SkipCount
+}
+
+// FailedCases returns all the failed test cases of all the suites
+func (r *TestReport) FailedCases() []TestCase {
+	var failed []TestCase
+	for _, suite := range r.Suites {
+		for _, testCase := range suite.Cases {
+			if testCase.Status.IsFailed() {
+				failed = append(failed, testCase)
+			}
+		}
+	}
+	return failed
+}
+
+// TestSuite is a set of test cases from a single report file
+type TestSuite struct {
+	Cases           []TestCase `json:"cases"`
+	DurationSeconds float64    `json:"duration"`
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Stderr          string     `json:"stderr"`
+	Stdout          string     `json:"stdout"`
+	Timestamp       string     `json:"timestamp"`
+}
+
+// Duration returns time spent by the suite
+func (s *TestSuite) Duration() time.Duration {
+	return secondsToDuration(s.DurationSeconds)
+}
+
+// TestCase is a result of a single test
+type TestCase struct {
+	// Age is a number of builds the test has been failing for
+	Age             int        `json:"age"`
+	ClassName       string     `json:"className"`
+	DurationSeconds float64    `json:"duration"`
+	ErrorDetails    string     `json:"errorDetails"`
+	ErrorStackTrace string     `json:"errorStackTrace"`
+	FailedSince     int        `json:"failedSince"`
+	Name            string     `json:"name"`
+	Skipped         bool       `json:"skipped"`
+	SkippedMessage  string     `json:"skippedMessage"`
+	Status          TestStatus `json:"status"`
+	Stderr          string     `json:"stderr"`
+	Stdout          string     `json:"stdout"`
+}
+
+// Duration returns time spent by the test
+func (c *TestCase) Duration() time.Duration {
+	return secondsToDuration(c.DurationSeconds)
+}
+
+// JUnit plugin reports durations in seconds
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,254 @@
+package jenkins
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Tree is a tree expression selecting fields of Jenkins API response
+// (value of "tree" query parameter), i. e. "name,builds[number,url]{0,10}";
+// trees are immutable, so a base tree may be safely shared and extended
+type Tree struct {
+	fields []treeField
+}
+
+type treeField struct {
+	name     string
+	children *Tree
+	// from and to limit range of array elements; to < 0 means no upper limit
+	ranged   bool
+	from, to int
+}
+
+// NewTree creates tree selecting given fields
+func NewTree(fields ...string) *Tree {
+	return (&Tree{}).Fields(fields...)
+}
+
+// Fields returns a copy of the tree with plain fields added; fields selected before are kept as is
+func (t *Tree) Fields(names ...string) *Tree {
+	result := t.clone()
+	for _, name := range names {
+		result.add(treeField{name: name}, false)
+	}
+	return result
+}
+
+// Nested returns a copy of the tree with field selecting its own fields;
+// it replaces the field with the same name selected before
+func (t *Tree) Nested(name string, children *Tree) *Tree {
+	result := t.clone()
+	result.add(treeField{name: name, children: children}, true)
+	return result
+}
+
+// NestedRange returns a copy of the tree with array field selecting its own fields of elements
+// from [from, to) range; negative to means no upper limit. It replaces the field with the same
+// name selected before
+func (t *Tree) NestedRange(name string, children *Tree, from, to int) *Tree {
+	result := t.clone()
+	result.add(treeField{name: name, children: children, ranged: true, from: from, to: to}, true)
+	return result
+}
+
+func (t *Tree) clone() *Tree {
+	if t == nil {
+		return &Tree{}
+	}
+	return &Tree{fields: append([]treeField(nil), t.fields...)}
+}
+
+// add appends field; the field with the same name selected before is kept unless replace is set
+func (t *Tree) add(field treeField, replace bool) {
+	for i, existing := range t.fields {
+		if existing.name == field.name {
+			if replace {
+				t.fields[i] = field
+			}
+			return
+		}
+	}
+	t.fields = append(t.fields, field)
+}
+
+func (t *Tree) String() string {
+	if t == nil {
+		return ""
+	}
+	rendered := make([]string, 0, len(t.fields))
+	for _, field := range t.fields {
+		var expression strings.Builder
+		expression.WriteString(field.name)
+		if children := field.children.String(); children != "" {
+			expression.WriteString("[" + children + "]")
+		}
+		if field.ranged {
+			if field.to < 0 {
+				expression.WriteString(fmt.Sprintf("{%d,}", field.from))
+			} else {
+				expression.WriteString(fmt.Sprintf("{%d,%d}", field.from, field.to))
+			}
+		}
+		rendered = append(rendered, expression.String())
+	}
+	return strings.Join(rendered, ",")
+}
+
+// treeExpressionSelects tells whether tree expression selects a given top-level field
+func treeExpressionSelects(expression, name string) bool {
+	var depth, start int
+	for i := 0; i <= len(expression); i++ {
+		if i < len(expression) {
+			switch expression[i] {
+			case '[', '{':
+				depth++
+				continue
+			case ']', '}':
+				depth--
+				continue
+			case ',':
+				if depth != 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		field := expression[start:i]
+		if end := strings.IndexAny(field, "[{"); end >= 0 {
+			field = field[:end]
+		}
+		if strings.TrimSpace(field) == name {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
+
+// TreeSelector is implemented by types that decode Jenkins API responses in a custom way
+// or hold objects that cannot be described by their Go types (interfaces for instance)
+type TreeSelector interface {
+	JenkinsTree() *Tree
+}
+
+var (
+	treeSelectorType  = reflect.TypeOf((*TreeSelector)(nil)).Elem()
+	jsonUnmarshalType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// TreeOf derives tree expression from json tags of a given value's type (struct or pointer to struct);
+// fields of struct types (including slices, pointers and values of maps) are selected recursively.
+// Other fields (interfaces, maps of interfaces, types with custom JSON decoding) are selected without
+// nested fields, and Jenkins returns only "_class" of objects selected this way, so types holding them
+// should implement TreeSelector
+func TreeOf(v interface{}) *Tree {
+	return treeOfType(reflect.TypeOf(v), nil)
+}
+
+// fieldsTree derives tree from fields of a given value's struct type even if it implements TreeSelector,
+// so that JenkinsTree implementations may extend it with nested selections of particular fields
+func fieldsTree(v interface{}) *Tree {
+	typ := reflect.TypeOf(v)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return treeOfStruct(typ, nil)
+}
+
+// treeOfType returns nil for types without selectable fields;
+// visiting lists types on the current path and prevents infinite recursion
+func treeOfType(typ reflect.Type, visiting []reflect.Type) *Tree {
+	if typ == nil {
+		return nil
+	}
+	if reflect.PtrTo(typ).Implements(treeSelectorType) {
+		return reflect.New(typ).Interface().(TreeSelector).JenkinsTree()
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return treeOfType(typ.Elem(), visiting)
+	case reflect.Map:
+		// Jenkins applies nested selection to every value of a map
+		if typ.Key().Kind() != reflect.String {
+			return nil
+		}
+		return treeOfType(typ.Elem(), visiting)
+	case reflect.Struct:
+		return treeOfStruct(typ, visiting)
+	default:
+		return nil
+	}
+}
+
+// treeOfStruct selects fields of struct type unless it has custom JSON decoding
+func treeOfStruct(typ reflect.Type, visiting []reflect.Type) *Tree {
+	if reflect.PtrTo(typ).Implements(jsonUnmarshalType) {
+		return nil
+	}
+	for _, visited := range visiting {
+		if visited == typ {
+			return nil
+		}
+	}
+	visiting = append(visiting, typ)
+
+	// Fields of embedded structs are shadowed by the fields of outer struct
+	tree := &Tree{}
+	var embedded []reflect.Type
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := jsonFieldName(field.Name)
+		tagName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tagName == "-" {
+			continue
+		}
+		if tagName != "" {
+			name = tagName
+		}
+
+		switch {
+		case field.Anonymous && tagName == "":
+			embedded = append(embedded, field.Type)
+		case field.PkgPath != "":
+			// unexported field
+		default:
+			tree.add(treeField{name: name, children: treeOfType(field.Type, visiting)}, true)
+		}
+	}
+	for _, embeddedType := range embedded {
+		if embeddedType.Kind() == reflect.Ptr {
+			embeddedType = embeddedType.Elem()
+		}
+		if embeddedTree := treeOfType(embeddedType, visiting); embeddedTree != nil {
+			for _, field := range embeddedTree.fields {
+				tree.add(field, false)
+			}
+		}
+	}
+	return tree
+}
+
+// jsonFieldName guesses Jenkins field name for an untagged Go field
+// by lowering its leading capital letters ("FirstBuild" -> "firstBuild", "URLName" -> "urlName");
+// encoding/json matches names case-insensitively, while tree expressions are case sensitive,
+// so the guess may be wrong ("absoluteUrl", "SHA1") and fields decoded from Jenkins responses
+// should always be tagged
+func jsonFieldName(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}